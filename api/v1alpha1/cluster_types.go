@@ -8,4 +8,123 @@ type ClusterStatus struct {
 
 	// KindClusterName is the name of the underlying kind cluster.
 	KindClusterName string `json:"kindClusterName"`
+
+	// KindVersion is the version of kind the cluster is managed with.
+	// +optional
+	KindVersion string `json:"kindVersion,omitempty"`
+
+	// ConfigHash is the hash of the kind configuration the cluster has been created with.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// CreationTimestamp is the time the node containers of the kind cluster have been created.
+	// +optional
+	CreationTimestamp *metav1.Time `json:"creationTimestamp,omitempty"`
+
+	// NodeImage is the image of the node containers.
+	// +optional
+	NodeImage string `json:"nodeImage,omitempty"`
+
+	// KubernetesVersion is the Kubernetes version of the kind cluster.
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// Nodes are the node containers of the kind cluster.
+	// +optional
+	Nodes []NodeStatus `json:"nodes,omitempty"`
+
+	// DockerHost is the name of the Docker host of the ProviderConfig the kind cluster has been placed on.
+	// +optional
+	DockerHost string `json:"dockerHost,omitempty"`
+
+	// Network is the Docker network the kind cluster runs in.
+	// +optional
+	Network string `json:"network,omitempty"`
+
+	// LoadBalancerSubnet is the subnet of the Docker network the LoadBalancer IPs of the kind cluster are assigned from.
+	// +optional
+	LoadBalancerSubnet string `json:"loadBalancerSubnet,omitempty"`
+
+	// PodSubnet is the pod subnet of the kind cluster if it has been assigned for cross-cluster connectivity.
+	// +optional
+	PodSubnet string `json:"podSubnet,omitempty"`
+
+	// ServiceSubnet is the service subnet of the kind cluster if it has been assigned for cross-cluster connectivity.
+	// +optional
+	ServiceSubnet string `json:"serviceSubnet,omitempty"`
+
+	// MetalLBVersion is the MetalLB version installed in the kind cluster.
+	// +optional
+	MetalLBVersion string `json:"metalLBVersion,omitempty"`
+
+	// DNS describes how the LoadBalancer services of the kind cluster are resolved if the DNS addon is enabled.
+	// +optional
+	DNS *DNSStatus `json:"dns,omitempty"`
+
+	// Bundles reports the state of the manifest bundles applied to the kind cluster.
+	// +optional
+	Bundles []BundleStatus `json:"bundles,omitempty"`
+
+	// PreloadedImages reports the state of the images that are preloaded into the nodes of the kind cluster.
+	// +optional
+	PreloadedImages []PreloadedImageStatus `json:"preloadedImages,omitempty"`
+}
+
+// NodeStatus describes a node container of a kind cluster.
+type NodeStatus struct {
+	// Name is the name of the node container.
+	Name string `json:"name"`
+
+	// ContainerID is the ID of the node container.
+	ContainerID string `json:"containerID"`
+
+	// IP is the IPv4 address of the node container in the Docker network.
+	// +optional
+	IP string `json:"ip,omitempty"`
+
+	// Role is the role of the node, e.g. control-plane or worker.
+	Role string `json:"role"`
+}
+
+// DNSStatus describes the DNS server that resolves the LoadBalancer services of a kind cluster.
+type DNSStatus struct {
+	// Server is the address of the DNS server on the host.
+	Server string `json:"server"`
+
+	// Domain is the domain of the records of the kind cluster. A service is resolved as <service>.<namespace>.<domain>.
+	Domain string `json:"domain"`
+}
+
+// BundleStatus is the state of a manifest bundle in a kind cluster.
+type BundleStatus struct {
+	// Name is the name of the bundle.
+	Name string `json:"name"`
+
+	// Ready is true if all objects of the bundle have been applied and are ready.
+	Ready bool `json:"ready"`
+
+	// Message describes why the bundle is not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Objects is the number of objects in the bundle.
+	// +optional
+	Objects int `json:"objects,omitempty"`
+
+	// Kinds are the kinds of the objects that have been applied. They are used to prune objects of the bundle.
+	// +optional
+	Kinds []metav1.GroupVersionKind `json:"kinds,omitempty"`
+}
+
+// PreloadedImageStatus is the state of an image that is preloaded into the nodes of a kind cluster.
+type PreloadedImageStatus struct {
+	// Name is the image reference or the path of the image archive.
+	Name string `json:"name"`
+
+	// Loaded is true if the image has been loaded into all nodes.
+	Loaded bool `json:"loaded"`
+
+	// Message describes why the image could not be loaded.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
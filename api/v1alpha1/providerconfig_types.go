@@ -6,14 +6,534 @@ import (
 )
 
 // ProviderConfigSpec defines the desired state of ProviderConfig
-type ProviderConfigSpec struct{}
+type ProviderConfigSpec struct {
+	// Addons lists the addons that are installed into every kind cluster, in the order of installation.
+	// Defaults to [MetalLB] if not set. An empty list disables all addons.
+	// Addons that are removed from the list are uninstalled from existing clusters.
+	// +optional
+	Addons []string `json:"addons,omitempty"`
 
-// ProviderConfigStatus defines the observed state of ProviderConfig
-type ProviderConfigStatus struct{}
+	// MetalLB configures the MetalLB installation in the kind clusters.
+	// +optional
+	MetalLB *MetalLBConfig `json:"metalLB,omitempty"`
+
+	// Ingress configures the ingress controller that is installed if the Ingress addon is enabled.
+	// +optional
+	Ingress *IngressConfig `json:"ingress,omitempty"`
+
+	// GatewayAPI configures the Gateway API CRDs and implementation that are installed if the GatewayAPI addon is enabled.
+	// +optional
+	GatewayAPI *GatewayAPIConfig `json:"gatewayAPI,omitempty"`
+
+	// Network configures the Docker networks the kind clusters are created in. It applies to clusters that are created
+	// after the change.
+	// +optional
+	Network *NetworkConfig `json:"network,omitempty"`
+
+	// CNI configures the network plugin of the kind clusters. Defaults to kindnet, which does not enforce
+	// NetworkPolicies. It applies to clusters that are created after the change.
+	// +optional
+	CNI *CNIConfig `json:"cni,omitempty"`
+
+	// Connectivity configures routes between the pod and service subnets of kind clusters, e.g. for multi-cluster
+	// service tests. It applies to clusters that are created after the change.
+	// +optional
+	Connectivity *ConnectivityConfig `json:"connectivity,omitempty"`
+
+	// LocalRegistry configures the local registry that is used by all kind clusters if the LocalRegistry addon is enabled.
+	// +optional
+	LocalRegistry *LocalRegistryConfig `json:"localRegistry,omitempty"`
+
+	// DNS configures the DNS server that is run if the DNS addon is enabled.
+	// +optional
+	DNS *DNSConfig `json:"dns,omitempty"`
+
+	// NodeImage is the kind node image the clusters are created with, e.g. kindest/node:v1.33.1. Its tag is the
+	// Kubernetes version of the clusters, which is published in the ClusterProfile. Defaults to the default node image
+	// of kind. It applies to clusters that are created after the change.
+	// +kubebuilder:validation:Pattern=`^[^@]+:v[0-9]+\.[0-9]+\.[0-9]+(@sha256:[a-f0-9]{64})?$`
+	// +optional
+	NodeImage string `json:"nodeImage,omitempty"`
+
+	// RegistryMirrors configures pull-through mirrors for image registries in containerd of all nodes.
+	// It applies to clusters that are created after the change.
+	// +listType=map
+	// +listMapKey=registry
+	// +optional
+	RegistryMirrors []RegistryMirror `json:"registryMirrors,omitempty"`
+
+	// Proxy configures the proxy used by all nodes. It applies to clusters that are created after the change.
+	// +optional
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+
+	// CACertificates are PEM encoded CA certificates that are trusted by all nodes in addition to the system roots,
+	// e.g. of a proxy or registry mirror. They apply to clusters that are created after the change.
+	// +optional
+	CACertificates []string `json:"caCertificates,omitempty"`
+
+	// PreloadImages lists images that are loaded into all nodes of every kind cluster, like `kind load`, so that they
+	// do not need to be pulled by the nodes. Each image is loaded once per cluster.
+	// +optional
+	PreloadImages []PreloadImage `json:"preloadImages,omitempty"`
+
+	// Bundles lists manifest bundles that are applied to every kind cluster after all addons are ready.
+	// The bundles are applied in order, each one after the objects of the previous bundle are ready.
+	// Objects of bundles that are removed from the list are deleted from existing clusters.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Bundles []ManifestBundle `json:"bundles,omitempty"`
+
+	// DockerHosts lists the Docker daemons the kind clusters are created on. Each new cluster is placed on one of them
+	// and stays there. Defaults to the Docker daemon of the provider.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	DockerHosts []DockerHost `json:"dockerHosts,omitempty"`
+
+	// Placement configures how new clusters are assigned to the Docker hosts.
+	// +optional
+	Placement *PlacementConfig `json:"placement,omitempty"`
+}
+
+// MetalLBConfig configures the MetalLB installation in the kind clusters.
+type MetalLBConfig struct {
+	// Version is the MetalLB version to install. It must be one of the versions embedded into the provider.
+	// Defaults to v0.14.9.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Images overrides the images of the MetalLB components, e.g. to pull them from a private registry.
+	// +optional
+	Images []ImageOverride `json:"images,omitempty"`
+
+	// Mode is the mode MetalLB uses to advertise the LoadBalancer IPs. Defaults to L2.
+	// +kubebuilder:validation:Enum=L2;BGP
+	// +optional
+	Mode MetalLBMode `json:"mode,omitempty"`
+
+	// BGP configures the BGP mode. It is only used if the mode is BGP.
+	// +optional
+	BGP *BGPConfig `json:"bgp,omitempty"`
+}
+
+// MetalLBMode is the mode MetalLB uses to advertise the LoadBalancer IPs.
+type MetalLBMode string
+
+const (
+	// MetalLBModeL2 advertises the LoadBalancer IPs via ARP.
+	MetalLBModeL2 MetalLBMode = "L2"
+	// MetalLBModeBGP advertises the LoadBalancer IPs via BGP to an FRR router container in the kind network.
+	MetalLBModeBGP MetalLBMode = "BGP"
+)
+
+// BGPConfig configures the FRR router container and the BGP sessions MetalLB establishes with it.
+type BGPConfig struct {
+	// RouterImage is the FRR image used for the router container. Defaults to quay.io/frrouting/frr:9.1.0.
+	// +optional
+	RouterImage string `json:"routerImage,omitempty"`
+
+	// RouterASN is the autonomous system number of the router. Defaults to 64512.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4294967295
+	// +optional
+	RouterASN int64 `json:"routerASN,omitempty"`
+
+	// ClusterASN is the autonomous system number MetalLB uses in every cluster. Defaults to 64513.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4294967295
+	// +optional
+	ClusterASN int64 `json:"clusterASN,omitempty"`
+
+	// SharedRouter runs a single router container for all clusters instead of one router container per cluster.
+	// +optional
+	SharedRouter bool `json:"sharedRouter,omitempty"`
+}
+
+// IngressConfig configures the ingress controller in the kind clusters.
+type IngressConfig struct {
+	// Images overrides the images of the ingress controller, e.g. to pull them from a private registry.
+	// +optional
+	Images []ImageOverride `json:"images,omitempty"`
+}
+
+// GatewayAPIConfig configures the Gateway API CRDs and implementation in the kind clusters.
+type GatewayAPIConfig struct {
+	// Channel is the release channel of the Gateway API CRDs. Defaults to Standard.
+	// +kubebuilder:validation:Enum=Standard;Experimental
+	// +optional
+	Channel GatewayAPIChannel `json:"channel,omitempty"`
+
+	// Images overrides the images of the Gateway API implementation, e.g. to pull them from a private registry.
+	// +optional
+	Images []ImageOverride `json:"images,omitempty"`
+}
+
+// GatewayAPIChannel is a release channel of the Gateway API CRDs.
+type GatewayAPIChannel string
+
+const (
+	// GatewayAPIChannelStandard contains the stable Gateway API resources.
+	GatewayAPIChannelStandard GatewayAPIChannel = "Standard"
+	// GatewayAPIChannelExperimental additionally contains experimental Gateway API resources and fields.
+	GatewayAPIChannelExperimental GatewayAPIChannel = "Experimental"
+)
+
+// NetworkIsolation defines which kind clusters share a Docker network.
+type NetworkIsolation string
+
+const (
+	// NetworkIsolationNone creates all clusters in the kind network.
+	NetworkIsolationNone NetworkIsolation = "None"
+	// NetworkIsolationCluster creates each cluster in its own network.
+	NetworkIsolationCluster NetworkIsolation = "Cluster"
+	// NetworkIsolationTenant creates the clusters of a tenant, i.e. the Clusters in the same namespace, in a shared network.
+	NetworkIsolationTenant NetworkIsolation = "Tenant"
+)
+
+// NetworkConfig configures the Docker networks the kind clusters are created in.
+type NetworkConfig struct {
+	// Isolation defines which clusters share a Docker network. Clusters in different networks cannot reach each
+	// other's API servers and LoadBalancer IPs. Defaults to None.
+	// +kubebuilder:validation:Enum=None;Cluster;Tenant
+	// +optional
+	Isolation NetworkIsolation `json:"isolation,omitempty"`
+}
+
+// CNIPlugin is a network plugin for the kind clusters.
+type CNIPlugin string
+
+const (
+	// CNIPluginKindnet is the default network plugin of kind.
+	CNIPluginKindnet CNIPlugin = "Kindnet"
+	// CNIPluginCalico installs Calico.
+	CNIPluginCalico CNIPlugin = "Calico"
+	// CNIPluginCilium installs Cilium.
+	CNIPluginCilium CNIPlugin = "Cilium"
+)
+
+// CNIConfig configures the network plugin of the kind clusters.
+type CNIConfig struct {
+	// Plugin is the network plugin. Defaults to Kindnet.
+	// +kubebuilder:validation:Enum=Kindnet;Calico;Cilium
+	// +optional
+	Plugin CNIPlugin `json:"plugin,omitempty"`
+
+	// PodSubnet is the CIDR of the pod IPs. Defaults to 10.244.0.0/16.
+	// +optional
+	PodSubnet string `json:"podSubnet,omitempty"`
+
+	// Images overrides the images of the network plugin, e.g. to pull them from a private registry.
+	// +optional
+	Images []ImageOverride `json:"images,omitempty"`
+}
+
+// ConnectivityConfig configures the routes between the pod and service subnets of kind clusters.
+type ConnectivityConfig struct {
+	// Enabled assigns non-overlapping pod and service subnets to new clusters and routes them between the clusters of
+	// the same connectivity group. The clusters of a group must run in the same Docker network.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// GroupLabel is the label of the Clusters whose value defines the connectivity group.
+	// Defaults to kind.clusters.openmcp.cloud/connectivity-group.
+	// +optional
+	GroupLabel string `json:"groupLabel,omitempty"`
+}
+
+// LocalRegistryConfig configures the local registry container that is shared by all kind clusters.
+type LocalRegistryConfig struct {
+	// Image is the registry image. Defaults to registry:2.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// HostPort is the port on the host the registry is published on. Defaults to 5001.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	HostPort int32 `json:"hostPort,omitempty"`
+}
+
+// DNSConfig configures the DNS server container that serves host names for the LoadBalancer services of all kind clusters.
+type DNSConfig struct {
+	// Image is the CoreDNS image. Defaults to coredns/coredns:1.12.1.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// HostPort is the port on the host the DNS server is published on, for UDP and TCP. Defaults to 1053.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	HostPort int32 `json:"hostPort,omitempty"`
+
+	// Domain is the domain the DNS server is authoritative for. Services are reachable as
+	// <service>.<namespace>.<cluster>.<cluster namespace>.<domain>. Defaults to kind.local.
+	// +optional
+	Domain string `json:"domain,omitempty"`
+}
+
+// RegistryMirror configures the endpoints from which the nodes pull the images of a registry.
+type RegistryMirror struct {
+	// Registry is the host of the mirrored registry, e.g. docker.io.
+	// +kubebuilder:validation:MinLength=1
+	Registry string `json:"registry"`
+
+	// Endpoints are the URLs of the mirrors, e.g. https://mirror.example.com. They are tried in order before the
+	// registry itself.
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []string `json:"endpoints"`
+}
+
+// ProxyConfig configures the proxy used by the nodes.
+type ProxyConfig struct {
+	// HTTPProxy is passed to the nodes as HTTP_PROXY.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is passed to the nodes as HTTPS_PROXY.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy lists hosts, domains and CIDRs that are reached without the proxy.
+	// The kind network, the LoadBalancer subnet of the cluster, the node names and the pod and service subnets are
+	// added automatically.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+}
+
+// PreloadImage is an image that is loaded into the nodes of the kind clusters.
+// Exactly one of image and archive must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.image) != has(self.archive)",message="exactly one of image and archive must be set"
+type PreloadImage struct {
+	// Image is the reference of an image in the Docker daemon the kind clusters run in. It is pulled if it does not exist.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Archive is the path of an image archive, as created by `docker save`, that is readable by the provider.
+	// +optional
+	Archive string `json:"archive,omitempty"`
+}
+
+// ImageOverride replaces an image that is referenced in an embedded manifest.
+type ImageOverride struct {
+	// Name is the image name as referenced in the manifest, without tag or digest.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// NewName replaces the name of the image.
+	// +optional
+	NewName string `json:"newName,omitempty"`
+
+	// NewTag replaces the tag of the image.
+	// +optional
+	NewTag string `json:"newTag,omitempty"`
+
+	// Digest replaces the tag of the image with a digest.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+}
+
+// ManifestBundle is a set of manifests that is applied to every kind cluster.
+// The manifests of all sources are combined and rendered with kustomize.
+type ManifestBundle struct {
+	// Name identifies the bundle. All objects of the bundle are labeled with it.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Inline contains manifests as multi-document YAML.
+	// +optional
+	Inline string `json:"inline,omitempty"`
+
+	// ConfigMapRef references a ConfigMap on the platform cluster whose keys are file names.
+	// If it contains a kustomization.yaml, the kustomization is rendered. Otherwise, all YAML files are applied.
+	// +optional
+	ConfigMapRef *ConfigMapReference `json:"configMapRef,omitempty"`
+
+	// Kustomization is an overlay that is applied on top of the manifests of the bundle.
+	// +optional
+	Kustomization *KustomizationOverlay `json:"kustomization,omitempty"`
+
+	// SkipWait disables waiting for the objects of the bundle to become ready before the next bundle is applied.
+	// +optional
+	SkipWait bool `json:"skipWait,omitempty"`
+}
+
+// ConfigMapReference references a ConfigMap.
+type ConfigMapReference struct {
+	// Name is the name of the ConfigMap.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the ConfigMap.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+// KustomizationOverlay configures a kustomize overlay for a manifest bundle.
+type KustomizationOverlay struct {
+	// Namespace sets the namespace of all namespaced objects of the bundle.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Labels are added to all objects of the bundle.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Images overrides images referenced in the manifests of the bundle.
+	// +optional
+	Images []ImageOverride `json:"images,omitempty"`
+
+	// Patches are strategic merge or JSON 6902 patches applied to the objects of the bundle.
+	// +optional
+	Patches []KustomizationPatch `json:"patches,omitempty"`
+}
+
+// KustomizationPatch is a patch that is applied to the objects of a manifest bundle.
+type KustomizationPatch struct {
+	// Patch is the content of the patch, either a strategic merge patch or a JSON 6902 patch.
+	// +kubebuilder:validation:MinLength=1
+	Patch string `json:"patch"`
+
+	// Target selects the objects the patch is applied to. It is required for JSON 6902 patches.
+	// +optional
+	Target *PatchTarget `json:"target,omitempty"`
+}
+
+// PatchTarget selects the objects a patch is applied to.
+type PatchTarget struct {
+	// +optional
+	Group string `json:"group,omitempty"`
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// DockerHost is a Docker daemon the kind clusters can be created on.
+type DockerHost struct {
+	// Name identifies the Docker host in the status of the clusters. Hosts must not be removed or renamed while
+	// clusters run on them.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Host is the address of the Docker daemon like DOCKER_HOST, e.g. unix:///var/run/docker.sock or
+	// tcp://docker.example.com:2376. Defaults to the Docker daemon of the provider.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// TLSCertPath is the directory with the client certificate (cert.pem, key.pem) and the CA (ca.pem) for a TCP host,
+	// like DOCKER_CERT_PATH. It must be readable by the provider, e.g. a mounted Secret. If set, TLS is verified.
+	// +optional
+	TLSCertPath string `json:"tlsCertPath,omitempty"`
+
+	// Labels are matched against the labels of the Clusters by the LabelMatch placement strategy.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// PlacementStrategy defines on which Docker host a new cluster is created.
+type PlacementStrategy string
+
+const (
+	// PlacementStrategyLeastLoaded places a cluster on the host with the fewest clusters.
+	PlacementStrategyLeastLoaded PlacementStrategy = "LeastLoaded"
+	// PlacementStrategyLabelMatch places a cluster on the least loaded host whose labels are all set on the Cluster.
+	PlacementStrategyLabelMatch PlacementStrategy = "LabelMatch"
+	// PlacementStrategyRoundRobin places a cluster on the host after the one the previous cluster has been placed on.
+	PlacementStrategyRoundRobin PlacementStrategy = "RoundRobin"
+)
+
+// PlacementConfig configures how new clusters are assigned to the Docker hosts.
+type PlacementConfig struct {
+	// Strategy is the placement strategy. Defaults to LeastLoaded.
+	// +kubebuilder:validation:Enum=LeastLoaded;LabelMatch;RoundRobin
+	// +optional
+	Strategy PlacementStrategy `json:"strategy,omitempty"`
+}
+
+// ProviderConfigStatus defines the observed state of ProviderConfig.
+// It reports the health of the Docker hosts and the networks the kind clusters run in and is refreshed periodically.
+type ProviderConfigStatus struct {
+	// ObservedGeneration is the generation of the ProviderConfig the status has been reported for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions report whether all Docker daemons are available (DockerAvailable) and whether there are free
+	// LoadBalancer subnets in all networks (LoadBalancerSubnetsAvailable).
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// DockerHosts reports the Docker daemons the kind clusters run on.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	DockerHosts []DockerHostStatus `json:"dockerHosts,omitempty"`
+
+	// Clusters is the number of kind clusters created with the ProviderConfig.
+	Clusters int32 `json:"clusters"`
+
+	// LastError is the error of the last check. It is empty if the check succeeded.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// DockerHostStatus reports a Docker daemon the kind clusters run on.
+type DockerHostStatus struct {
+	// Name is the name of the Docker host.
+	Name string `json:"name"`
+
+	// Reachable is true if the Docker daemon responded to the last check.
+	Reachable bool `json:"reachable"`
+
+	// Version is the version of the Docker daemon.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Clusters is the number of kind clusters of the ProviderConfig on the Docker host.
+	Clusters int32 `json:"clusters"`
+
+	// Networks reports the Docker networks of the kind clusters.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Networks []NetworkStatus `json:"networks,omitempty"`
+
+	// NodeImages lists the kind node images that are available in the Docker daemon.
+	// +optional
+	NodeImages []string `json:"nodeImages,omitempty"`
+}
+
+// NetworkStatus reports a Docker network the kind clusters run in.
+type NetworkStatus struct {
+	// Name is the name of the Docker network.
+	Name string `json:"name"`
+
+	// Subnet is the IPv4 subnet of the Docker network in CIDR notation.
+	// +optional
+	Subnet string `json:"subnet,omitempty"`
+
+	// FreeLoadBalancerSubnets is the number of LoadBalancer subnets in the network that can be assigned to new clusters.
+	FreeLoadBalancerSubnets int32 `json:"freeLoadBalancerSubnets"`
+}
 
 // ProviderConfig is the Schema for the ProviderConfig API.
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Docker",type=string,JSONPath=`.status.conditions[?(@.type=="DockerAvailable")].status`
+// +kubebuilder:printcolumn:name="Clusters",type=integer,JSONPath=`.status.clusters`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:metadata:labels="openmcp.cloud/cluster=platform"
 type ProviderConfig struct {
@@ -22,13 +22,17 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,22 +44,34 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
-	"github.com/openmcp-project/openmcp-operator/api/common"
 
 	"github.com/openmcp-project/controller-utils/pkg/controller/smartrequeue"
 
 	"github.com/openmcp-project/cluster-provider-kind/api/crds"
 	kindv1alpha1 "github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
 	"github.com/openmcp-project/cluster-provider-kind/internal/controller"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/dns"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/gatewayapi"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/ingress"
 	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/metallb"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/registry"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/tracing"
 	// +kubebuilder:scaffold:imports
 )
 
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
+
+	// noMatchBackoff retries the creation of the default ProviderConfig for about half a minute until its kind is served.
+	noMatchBackoff = wait.Backoff{Steps: 5, Duration: time.Second, Factor: 2, Jitter: 0.1}
 )
 
+// crdEstablishedTimeout is the time the init command waits for the CRDs to be established.
+const crdEstablishedTimeout = 2 * time.Minute
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
@@ -87,45 +103,63 @@ func runInit(setupClient client.Client) {
 		}
 	}
 
-	cp := &clustersv1alpha1.ClusterProfile{
+	setupLog.Info("Waiting for CRDs to be established")
+	err = wait.PollUntilContextTimeout(initContext, time.Second, crdEstablishedTimeout, true, func(ctx context.Context) (bool, error) {
+		return crdsEstablished(ctx, setupClient, crds)
+	})
+	if err != nil {
+		setupLog.Error(err, "CRDs have not been established")
+		os.Exit(1)
+	}
+
+	// The ClusterProfiles are generated by the ProviderConfig controller. The default ProviderConfig is created without
+	// any settings, unless it exists already. The API server may take a moment to serve an established CRD.
+	pc := &kindv1alpha1.ProviderConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "kind",
 		},
-		Spec: clustersv1alpha1.ClusterProfileSpec{
-			ProviderRef: common.LocalObjectReference{
-				Name: "kind",
-			},
-			ProviderConfigRef: common.LocalObjectReference{
-				Name: "kind",
-			},
-			SupportedVersions: []clustersv1alpha1.SupportedK8sVersion{},
-		},
 	}
-
-	_, err = controllerutil.CreateOrUpdate(initContext, setupClient, cp, func() error {
-		return nil
+	err = retry.OnError(noMatchBackoff, meta.IsNoMatchError, func() error {
+		return client.IgnoreAlreadyExists(setupClient.Create(initContext, pc))
 	})
-
 	if err != nil {
-		setupLog.Error(err, "Failed to create/update ClusterProfile", "name", cp.Name)
+		setupLog.Error(err, "Failed to create ProviderConfig", "name", pc.Name)
 		os.Exit(1)
 	}
 
 	setupLog.Info("Init command completed successfully")
 }
 
+// crdsEstablished returns whether all given CRDs have the Established condition.
+func crdsEstablished(ctx context.Context, c client.Client, crds []*apiextensionsv1.CustomResourceDefinition) (bool, error) {
+	for _, crd := range crds {
+		current := &apiextensionsv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(crd), current); err != nil {
+			return false, err
+		}
+		established := slices.ContainsFunc(current.Status.Conditions, func(c apiextensionsv1.CustomResourceDefinitionCondition) bool {
+			return c.Type == apiextensionsv1.Established && c.Status == apiextensionsv1.ConditionTrue
+		})
+		if !established {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // nolint:gocyclo
 func main() {
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
-	var enableLeaderElection bool
+	var enableLeaderElection, enableWebhooks bool
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
-	var providerName string
+	var configFile string
 	var tlsOpts []func(*tls.Config)
-	var environment, verbosity string
+	var verbosity string
+	flags := controller.DefaultConfig()
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -134,6 +168,9 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the validating and defaulting webhooks for Clusters and AccessRequests are served. "+
+			"They must be registered in the platform cluster, see config/webhook.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
 	flag.StringVar(&webhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
 	flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
@@ -143,9 +180,9 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
-	flag.StringVar(&environment, "environment", "", "The name of the environment to use for the provider.")
 	flag.StringVar(&verbosity, "verbosity", "", "The verbosity level for the logger.")
-	flag.StringVar(&providerName, "provider-name", "kind", "The name of the provider. This is used to identify the provider in logs and metrics.")
+	flag.StringVar(&configFile, "config", "", "The configuration file of the provider. Flags and environment variables take precedence.")
+	flags.BindFlags(flag.CommandLine)
 	opts := zap.Options{
 		Development: true,
 	}
@@ -260,18 +297,14 @@ func main() {
 		})
 	}
 
-	kindConfigFile := os.Getenv("KIND_CONFIG_FILE")
-	setupLog.Info("KIND SETUP", "CONFIG FILE", kindConfigFile)
-
-	kindProvider := kind.NewKindProvider(kindConfigFile)
-
-	accessRequestServiceAccountNamespace := os.Getenv("ACCESS_REQUEST_SERVICE_ACCOUNT_NAMESPACE")
-	if accessRequestServiceAccountNamespace == "" {
-		accessRequestServiceAccountNamespace = "accessrequests"
+	config, err := controller.LoadConfig(flag.CommandLine, flags, configFile)
+	if err != nil {
+		setupLog.Error(err, "invalid configuration")
+		os.Exit(1)
 	}
-	controller.SetAccessRequestServiceAccountNamespace(accessRequestServiceAccountNamespace)
-	controller.SetProviderName(providerName)
-	controller.SetEnvironment(environment)
+	setupLog.Info("Loaded configuration", "config", config)
+
+	kindProvider := kind.NewKindProvider(config.KindConfigFile)
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
@@ -298,24 +331,59 @@ func main() {
 	}
 
 	if err = (&controller.ClusterReconciler{
+		Config:       config,
 		Client:       mgr.GetClient(),
 		Scheme:       mgr.GetScheme(),
 		RequeueStore: smartrequeue.NewStore(10*time.Second, 10*time.Minute, 1.5),
 		Provider:     kindProvider,
+		Recorder:     mgr.GetEventRecorder("cluster-provider-kind"),
+		Addons: []addon.Addon{
+			metallb.NewAddon(),
+			ingress.NewAddon(),
+			gatewayapi.NewAddon(),
+			registry.NewAddon(),
+			dns.NewAddon(),
+		},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		os.Exit(1)
 	}
 	if err = (&controller.AccessRequestReconciler{
-		ProviderName:       providerName,
+		Config:             config,
 		Client:             mgr.GetClient(),
 		Scheme:             mgr.GetScheme(),
 		KubeConfigProvider: kindProvider,
 		ClientProvider:     controller.NewClientProvider(kindProvider),
+		Recorder:           mgr.GetEventRecorder("cluster-provider-kind"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AccessRequest")
 		os.Exit(1)
 	}
+	if err = (&controller.ProviderConfigReconciler{
+		Config:   config,
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Host:     kind.NewHost(),
+		Recorder: mgr.GetEventRecorder("cluster-provider-kind"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ProviderConfig")
+		os.Exit(1)
+	}
+	if enableWebhooks {
+		if err = (&controller.ClusterWebhook{
+			Config: config,
+			Client: mgr.GetClient(),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Cluster")
+			os.Exit(1)
+		}
+		if err = (&controller.AccessRequestWebhook{
+			Config: config,
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AccessRequest")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -343,9 +411,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx := ctrl.SetupSignalHandler()
+	shutdownTracing, err := tracing.Setup(ctx)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	// The signal context is done, so the remaining spans are flushed with a fresh one.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		setupLog.Error(err, "unable to shut down tracing")
+	}
 }
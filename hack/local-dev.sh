@@ -175,22 +175,22 @@ data:
         mcp:
           template:
             spec:
-              profile: kind
+              profile: kind.kind
               tenancy: Exclusive
         platform:
           template:
             spec:
-              profile: kind
+              profile: kind.kind
               tenancy: Shared
         onboarding:
           template:
             spec:
-              profile: kind
+              profile: kind.kind
               tenancy: Shared
         workload:
           template:
             spec:
-              profile: kind
+              profile: kind.kind
               tenancy: Shared
 EOF
 }
@@ -324,7 +324,7 @@ metadata:
   namespace: openmcp-system
 spec:
   kubernetes: {}
-  profile: kind
+  profile: kind.kind
   purposes:
   - platform
   tenancy: Shared
@@ -38,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -50,6 +51,9 @@ import (
 	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
 	commonapi "github.com/openmcp-project/openmcp-operator/api/common"
 	libutils "github.com/openmcp-project/openmcp-operator/lib/utils"
+
+	"github.com/openmcp-project/cluster-provider-kind/pkg/metrics"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/tracing"
 )
 
 const (
@@ -57,6 +61,7 @@ const (
 	managedByNameLabel      = groupName + "/managed-by-name"
 	managedByNamespaceLabel = groupName + "/managed-by-namespace"
 	kindRole                = "Role"
+	kindClusterRole         = "ClusterRole"
 
 	refreshTokenPercentage = 0.8
 
@@ -75,21 +80,24 @@ var (
 
 // AccessRequestReconciler reconciles a AccessRequest object
 type AccessRequestReconciler struct {
-	ProviderName string
+	Config Config
 	client.Client
 	Scheme             *runtime.Scheme
 	KubeConfigProvider KubeConfigProvider
 	ClientProvider     ClientProvider
+	Recorder           events.EventRecorder
+
+	errorEvents errorEvents
 }
 
 // ClientProvider creates a client for a cluster
 type ClientProvider interface {
-	CreateClient(clusterName string) (client.Client, *rest.Config, error)
+	CreateClient(ctx context.Context, clusterName string) (client.Client, *rest.Config, error)
 }
 
 // KubeConfigProvider retrieves the kubeconfig of a cluster
 type KubeConfigProvider interface {
-	KubeConfig(name string, localhost bool) (string, error)
+	KubeConfig(ctx context.Context, name string, localhost bool) (string, error)
 }
 
 type clientProviderImpl struct {
@@ -104,8 +112,8 @@ func NewClientProvider(p KubeConfigProvider) ClientProvider {
 }
 
 // CreateClient implements [ClientProvider].
-func (r clientProviderImpl) CreateClient(clusterName string) (client.Client, *rest.Config, error) {
-	kubeconfig, err := r.configProvider.KubeConfig(clusterName, false)
+func (r clientProviderImpl) CreateClient(ctx context.Context, clusterName string) (client.Client, *rest.Config, error) {
+	kubeconfig, err := r.configProvider.KubeConfig(ctx, clusterName, false)
 	if err != nil {
 		return nil, nil, errutils.WithReason(err, reasonKindClusterInteractionError)
 	}
@@ -122,7 +130,10 @@ func (r clientProviderImpl) CreateClient(clusterName string) (client.Client, *re
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *AccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *AccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "AccessRequestReconciler.Reconcile", tracing.AttrNamespace.String(req.Namespace), tracing.AttrName.String(req.Name))
+	defer func() { tracing.End(span, err) }()
+
 	log := log.FromContext(ctx)
 	log.Info("Reconcile")
 	defer log.Info("Done")
@@ -133,7 +144,7 @@ func (r *AccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	arCopy := ar.DeepCopy()
 
-	if !libutils.IsClusterProviderResponsibleForAccessRequest(ar, r.ProviderName) {
+	if !libutils.IsClusterProviderResponsibleForAccessRequest(ar, r.Config.ProviderName) {
 		log.Info("ClusterProvider is not responsible for this AccessRequest, skipping reconciliation")
 		return ctrl.Result{}, nil
 	}
@@ -146,10 +157,15 @@ func (r *AccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	cluster := &clustersv1alpha1.Cluster{}
 	if err := r.Get(ctx, clusterRef, cluster); err != nil {
 		return ctrl.Result{}, r.updateStatus(ctx, ar, arCopy, fmt.Errorf("%s: %w", reasonInvalidReference, err))
-	} else if !isClusterProviderResponsible(cluster) { // TODO: should be refactored
+	} else if !isClusterProviderResponsible(r.Config.ProviderName, cluster) { // TODO: should be refactored
 		return ctrl.Result{}, r.updateStatus(ctx, ar, arCopy, fmt.Errorf("%s: ClusterProfile '%s' is not supported by kind controller", reasonNotResponsible, cluster.Spec.Profile))
 	}
 
+	ctx, err = withClusterDockerHost(ctx, r.Client, r.Config.ProviderName, cluster)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, ar, arCopy, err)
+	}
+
 	if !ar.DeletionTimestamp.IsZero() {
 		if err := r.handleDelete(ctx, ar, cluster); err != nil {
 			return ctrl.Result{}, r.updateStatus(ctx, ar, arCopy, err)
@@ -163,6 +179,7 @@ func (r *AccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 func (r *AccessRequestReconciler) updateStatus(ctx context.Context, ar, arCopy *clustersv1alpha1.AccessRequest, reconcileError error) error {
 	if reconcileError != nil {
+		r.errorEvents.record(r.Recorder, ar, reconcileError)
 		meta.SetStatusCondition(&ar.Status.Conditions, metav1.Condition{
 			Type:               "Ready",
 			Status:             metav1.ConditionFalse,
@@ -172,6 +189,7 @@ func (r *AccessRequestReconciler) updateStatus(ctx context.Context, ar, arCopy *
 			LastTransitionTime: metav1.Now(),
 		})
 	} else {
+		r.errorEvents.reset(ar)
 		meta.SetStatusCondition(&ar.Status.Conditions, metav1.Condition{
 			Type:               "Ready",
 			Status:             metav1.ConditionTrue,
@@ -217,7 +235,7 @@ func (r *AccessRequestReconciler) handleCreateOrUpdate(ctx context.Context, ar *
 		return res, nil
 	}
 
-	cl, restCfg, err := r.ClientProvider.CreateClient(name)
+	cl, restCfg, err := r.ClientProvider.CreateClient(ctx, name)
 	if err != nil {
 		return ctrl.Result{}, errutils.WithReason(err, reasonKindClusterInteractionError)
 	}
@@ -238,7 +256,7 @@ func (r *AccessRequestReconciler) handleCreateOrUpdate(ctx context.Context, ar *
 
 func (r *AccessRequestReconciler) reconcileOIDCAccess(ctx context.Context, clusterName string, ar *clustersv1alpha1.AccessRequest) (ctrl.Result, error) {
 	// TODO: proper access permission processing instead of providing admin access
-	kubeconfigStr, err := r.KubeConfigProvider.KubeConfig(clusterName, false)
+	kubeconfigStr, err := r.KubeConfigProvider.KubeConfig(ctx, clusterName, false)
 	if err != nil {
 		return ctrl.Result{}, errutils.WithReason(err, reasonKindClusterInteractionError)
 	}
@@ -278,7 +296,7 @@ func (r *AccessRequestReconciler) reconcileOIDCAccess(ctx context.Context, clust
 
 // setLocalhostKindAnnotation resolves the localhost API server URL for the cluster and writes it as an annotation on the AccessRequest.
 func (r *AccessRequestReconciler) setLocalhostKindAnnotation(ctx context.Context, ar *clustersv1alpha1.AccessRequest, clusterName string) error {
-	localhostKubeconfig, err := r.KubeConfigProvider.KubeConfig(clusterName, true)
+	localhostKubeconfig, err := r.KubeConfigProvider.KubeConfig(ctx, clusterName, true)
 	if err != nil {
 		return errutils.WithReason(fmt.Errorf("failed to `get localhost kubeconfig: %w", err), reasonKindClusterInteractionError)
 	}
@@ -298,7 +316,7 @@ func (r *AccessRequestReconciler) setLocalhostKindAnnotation(ctx context.Context
 
 func (r *AccessRequestReconciler) handleDelete(ctx context.Context, ar *clustersv1alpha1.AccessRequest, cluster *clustersv1alpha1.Cluster) error {
 	name := kindName(cluster)
-	cl, _, err := r.ClientProvider.CreateClient(name)
+	cl, _, err := r.ClientProvider.CreateClient(ctx, name)
 	if err != nil {
 		return errutils.WithReason(err, reasonKindClusterInteractionError)
 	}
@@ -311,6 +329,8 @@ func (r *AccessRequestReconciler) handleDelete(ctx context.Context, ar *clusters
 			return errutils.WithReason(fmt.Errorf("error patching finalizer on resource: %w", err), reasonKindClusterInteractionError)
 		}
 	}
+	metrics.AccessTokenExpiration.DeleteLabelValues(ar.Namespace, ar.Name)
+	r.errorEvents.reset(ar)
 	return nil
 }
 
@@ -320,7 +340,7 @@ func (r *AccessRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&clustersv1alpha1.AccessRequest{}).
 		WithEventFilter(
 			predicate.NewPredicateFuncs(func(obj client.Object) bool {
-				return libutils.IsClusterProviderResponsibleForAccessRequest(obj.(*clustersv1alpha1.AccessRequest), r.ProviderName)
+				return libutils.IsClusterProviderResponsibleForAccessRequest(obj.(*clustersv1alpha1.AccessRequest), r.Config.ProviderName)
 			}),
 		).
 		Named("accessrequest").
@@ -424,28 +444,31 @@ func managedResourcesLabels(ac *clustersv1alpha1.AccessRequest) map[string]strin
 // reconcileTokenAccess creates a service account token that reflects the requested cluster access
 // this includes reconciliation of the service account, the related (cluster) roles and (cluster) bindings in the cluster the access request is for
 // and eventually creating a corresponding secret that holds the prepared kubeconfig in the platform cluster
-func (r *AccessRequestReconciler) reconcileTokenAccess(ctx context.Context, c client.Client, cfg *rest.Config, ar *clustersv1alpha1.AccessRequest) ([]client.Object, *time.Duration, error) {
+func (r *AccessRequestReconciler) reconcileTokenAccess(ctx context.Context, c client.Client, cfg *rest.Config, ar *clustersv1alpha1.AccessRequest) (_ []client.Object, _ *time.Duration, err error) {
+	ctx, span := tracing.Start(ctx, "AccessRequestReconciler.IssueToken", tracing.AttrNamespace.String(ar.Namespace), tracing.AttrName.String(ar.Name))
+	defer func() { tracing.End(span, err) }()
+
 	log := log.FromContext(ctx)
 	log.Info("reconcile token access")
 
 	// ensure namespace
-	_, err := clusteraccess.EnsureNamespace(ctx, c, AccessRequestServiceAccountNamespace())
+	_, err = clusteraccess.EnsureNamespace(ctx, c, r.Config.AccessRequestServiceAccountNamespace)
 	if err != nil {
-		return nil, nil, errutils.WithReason(fmt.Errorf("create namespace %s failed: %w", AccessRequestServiceAccountNamespace(), err), reasonKindClusterInteractionError)
+		return nil, nil, errutils.WithReason(fmt.Errorf("create namespace %s failed: %w", r.Config.AccessRequestServiceAccountNamespace, err), reasonKindClusterInteractionError)
 	}
 
 	// ensure service account
-	name := ctrlutils.NameHashSHAKE128Base32(Environment(), ProviderName(), ar.Namespace, ar.Name)
-	sa, err := clusteraccess.EnsureServiceAccount(ctx, c, name, AccessRequestServiceAccountNamespace(), pairs.MapToPairs(managedResourcesLabels(ar))...)
+	name := ctrlutils.NameHashSHAKE128Base32(r.Config.Environment, r.Config.ProviderName, ar.Namespace, ar.Name)
+	sa, err := clusteraccess.EnsureServiceAccount(ctx, c, name, r.Config.AccessRequestServiceAccountNamespace, pairs.MapToPairs(managedResourcesLabels(ar))...)
 	if err != nil {
-		return nil, nil, errutils.WithReason(fmt.Errorf("create service account %s/%s failed: %w", AccessRequestServiceAccountNamespace(), name, err), reasonKindClusterInteractionError)
+		return nil, nil, errutils.WithReason(fmt.Errorf("create service account %s/%s failed: %w", r.Config.AccessRequestServiceAccountNamespace, name, err), reasonKindClusterInteractionError)
 	}
 
-	permObjs, errlist := reconcileRequestedPermissions(ctx, c, sa, ar)
+	permObjs, errlist := r.reconcileRequestedPermissions(ctx, c, sa, ar)
 	if err := errlist.Aggregate(); err != nil {
 		return nil, nil, err
 	}
-	bindObjs, errlist := reconcileRequestedRoleBindings(ctx, c, sa, ar)
+	bindObjs, errlist := r.reconcileRequestedRoleBindings(ctx, c, sa, ar)
 	if err := errlist.Aggregate(); err != nil {
 		return nil, nil, err
 	}
@@ -460,7 +483,7 @@ func (r *AccessRequestReconciler) reconcileTokenAccess(ctx context.Context, c cl
 	requeueAfter := time.Until(clusteraccess.ComputeTokenRenewalTimeWithRatio(token.CreationTimestamp, token.ExpirationTimestamp, refreshTokenPercentage))
 
 	// create kubeconfig
-	kcfg, err := clusteraccess.CreateTokenKubeconfig(ProviderName(), cfg.Host, cfg.CAData, token.Token)
+	kcfg, err := clusteraccess.CreateTokenKubeconfig(r.Config.ProviderName, cfg.Host, cfg.CAData, token.Token)
 	if err != nil {
 		return nil, nil, errutils.WithReason(fmt.Errorf("create token kubeconfig failed: %w", err), reasonInternalError)
 	}
@@ -485,15 +508,22 @@ func (r *AccessRequestReconciler) reconcileTokenAccess(ctx context.Context, c cl
 		return nil, nil, errutils.WithReason(fmt.Errorf("create/update kubeconfig secret failed: %w", err), reasonKindClusterInteractionError)
 	}
 
+	if ar.Status.SecretRef != nil {
+		r.Recorder.Eventf(ar, nil, corev1.EventTypeNormal, "TokenRotated", "IssueToken", "Rotated token, expires at %s", token.ExpirationTimestamp.UTC().Format(time.RFC3339))
+	} else {
+		r.Recorder.Eventf(ar, nil, corev1.EventTypeNormal, "TokenIssued", "IssueToken", "Issued token, expires at %s", token.ExpirationTimestamp.UTC().Format(time.RFC3339))
+	}
+
 	ar.Status.SecretRef = &commonapi.LocalObjectReference{
 		Name: s.Name,
 	}
 	ar.Status.Phase = clustersv1alpha1.REQUEST_GRANTED
+	metrics.AccessTokenExpiration.WithLabelValues(ar.Namespace, ar.Name).Set(float64(token.ExpirationTimestamp.Unix()))
 
 	return keep, &requeueAfter, nil
 }
 
-func reconcileRequestedPermissions(ctx context.Context, c client.Client, sa *corev1.ServiceAccount, ar *clustersv1alpha1.AccessRequest) ([]client.Object, errutils.ReasonableErrorList) {
+func (r *AccessRequestReconciler) reconcileRequestedPermissions(ctx context.Context, c client.Client, sa *corev1.ServiceAccount, ar *clustersv1alpha1.AccessRequest) ([]client.Object, errutils.ReasonableErrorList) {
 	log := log.FromContext(ctx)
 	// ensure roles + bindings
 	keep := []client.Object{}
@@ -503,7 +533,7 @@ func reconcileRequestedPermissions(ctx context.Context, c client.Client, sa *cor
 	for i, permission := range ar.Spec.Token.Permissions {
 		roleName := permission.Name
 		if roleName == "" {
-			roleName = fmt.Sprintf("openmcp:permission:%s:%d", ctrlutils.NameHashSHAKE128Base32(Environment(), ProviderName(), ar.Namespace, ar.Name), i)
+			roleName = fmt.Sprintf("openmcp:permission:%s:%d", ctrlutils.NameHashSHAKE128Base32(r.Config.Environment, r.Config.ProviderName, ar.Namespace, ar.Name), i)
 		}
 		if permission.Namespace != "" {
 			// ensure namespace for role + binding if not disabled
@@ -536,14 +566,14 @@ func reconcileRequestedPermissions(ctx context.Context, c client.Client, sa *cor
 	return keep, *errlist
 }
 
-func reconcileRequestedRoleBindings(ctx context.Context, c client.Client, sa *corev1.ServiceAccount, ar *clustersv1alpha1.AccessRequest) ([]client.Object, errutils.ReasonableErrorList) {
+func (r *AccessRequestReconciler) reconcileRequestedRoleBindings(ctx context.Context, c client.Client, sa *corev1.ServiceAccount, ar *clustersv1alpha1.AccessRequest) ([]client.Object, errutils.ReasonableErrorList) {
 	keep := []client.Object{}
 	errlist := errutils.NewReasonableErrorList()
 	expectedLabels := pairs.MapToPairs(managedResourcesLabels(ar))
 	subjects := []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: sa.Name, Namespace: sa.Namespace}}
 	// ensure ServiceAccount is bound to (Cluster)Roles
 	for i, roleRef := range ar.Spec.Token.RoleRefs {
-		roleBindingName := fmt.Sprintf("openmcp:roleref:%s:%d", ctrlutils.NameHashSHAKE128Base32(Environment(), ProviderName(), ar.Namespace, ar.Name), i)
+		roleBindingName := fmt.Sprintf("openmcp:roleref:%s:%d", ctrlutils.NameHashSHAKE128Base32(r.Config.Environment, r.Config.ProviderName, ar.Namespace, ar.Name), i)
 		if roleRef.Kind == kindRole {
 			// Role
 			rb, err := clusteraccess.EnsureRoleBinding(ctx, c, roleBindingName, roleRef.Namespace, roleRef.Name, subjects, expectedLabels...)
@@ -602,6 +632,7 @@ func (r *AccessRequestReconciler) tokenRefreshRequired(ctx context.Context, ar *
 		tokenRenewalTime := createdAt.Add(time.Duration(float64(expiredAt.Sub(createdAt)) * refreshTokenPercentage))
 		if time.Now().Before(tokenRenewalTime) {
 			// the request is granted, the secret still exists and the token is still valid - nothing to do
+			metrics.AccessTokenExpiration.WithLabelValues(ar.Namespace, ar.Name).Set(float64(expiredAt.Unix()))
 			return ctrl.Result{
 				RequeueAfter: time.Until(tokenRenewalTime),
 			}, nil
@@ -20,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,9 +37,11 @@ const (
 
 func TestAccessRequestReconciler_Reconcile(t *testing.T) {
 	providerName := "kind"
-	SetAccessRequestServiceAccountNamespace("accessrequest")
-	SetEnvironment("unit-test")
-	SetProviderName(providerName)
+	config := Config{
+		ProviderName:                         providerName,
+		Environment:                          "unit-test",
+		AccessRequestServiceAccountNamespace: "accessrequest",
+	}
 	kindClusterRole := "ClusterRole"
 	scheme := runtime.NewScheme()
 	_ = clientgoscheme.AddToScheme(scheme)
@@ -55,6 +58,7 @@ func TestAccessRequestReconciler_Reconcile(t *testing.T) {
 		wantReason           string
 		wantResourceCreation bool
 		wantRefresh          bool
+		wantEvents           []string
 	}{
 		{
 			name: "oidc processing return cluster provider kubeconfig",
@@ -99,6 +103,7 @@ func TestAccessRequestReconciler_Reconcile(t *testing.T) {
 			wantReason:           reasonKindClusterInteractionError,
 			wantResourceCreation: false,
 			wantRefresh:          false,
+			wantEvents:           []string{reasonKindClusterInteractionError},
 		},
 		{
 			name: "create and delete success",
@@ -151,6 +156,7 @@ func TestAccessRequestReconciler_Reconcile(t *testing.T) {
 			wantErr:              false,
 			wantResourceCreation: true,
 			wantRefresh:          false,
+			wantEvents:           []string{"TokenIssued"},
 		},
 		{
 			name: "refresh expired token",
@@ -183,6 +189,7 @@ func TestAccessRequestReconciler_Reconcile(t *testing.T) {
 			wantErr:              false,
 			wantResourceCreation: false,
 			wantRefresh:          true,
+			wantEvents:           []string{"TokenRotated"},
 		},
 		{
 			name: "skip refresh of non-expired token",
@@ -219,8 +226,9 @@ func TestAccessRequestReconciler_Reconcile(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			recorder := events.NewFakeRecorder(10)
 			r := AccessRequestReconciler{
-				ProviderName: providerName,
+				Config: config,
 				Client: fake.NewClientBuilder().
 					WithScheme(scheme).
 					WithObjects(buildFakeObject(tt.ar, tt.kubeconfigSecret)...).
@@ -229,6 +237,7 @@ func TestAccessRequestReconciler_Reconcile(t *testing.T) {
 				Scheme:             scheme,
 				KubeConfigProvider: fakeKindConfigProvider{},
 				ClientProvider:     tt.clientProvider,
+				Recorder:           recorder,
 			}
 			ctx := ctrl.LoggerInto(context.Background(), zap.New(zap.UseDevMode(true)))
 
@@ -236,6 +245,9 @@ func TestAccessRequestReconciler_Reconcile(t *testing.T) {
 
 			got, gotErr := r.Reconcile(ctx, tt.req)
 
+			// ### ASSERT EVENTS ###
+			assert.Equal(t, tt.wantEvents, eventReasons(recorder))
+
 			// ### ASSERT ERROR ###
 			if gotErr != nil {
 				if !tt.wantErr {
@@ -296,12 +308,12 @@ func TestAccessRequestReconciler_Reconcile(t *testing.T) {
 
 			// assert service account exists for this access request
 			saList := &corev1.ServiceAccountList{}
-			requestedClusterClient, _, _ := tt.clientProvider.CreateClient("")
+			requestedClusterClient, _, _ := tt.clientProvider.CreateClient(ctx, "")
 			err = requestedClusterClient.List(ctx, saList)
 			assert.NoError(t, err)
 			assert.Len(t, saList.Items, 1)
 			sa := saList.Items[0]
-			assert.Equal(t, accessRequestServiceAccountNamespace, sa.GetNamespace())
+			assert.Equal(t, config.AccessRequestServiceAccountNamespace, sa.GetNamespace())
 
 			// assert cluster role exists and has expected rules
 			clusterRole := &rbacv1.ClusterRole{
@@ -492,7 +504,7 @@ type configuredKubeConfigProvider struct {
 	err        error
 }
 
-func (f configuredKubeConfigProvider) KubeConfig(_ string, _ bool) (string, error) {
+func (f configuredKubeConfigProvider) KubeConfig(_ context.Context, _ string, _ bool) (string, error) {
 	return f.kubeconfig, f.err
 }
 
@@ -524,7 +536,7 @@ type fakeClientProvider struct {
 }
 
 // CreateClient implements [ClientProvider].
-func (f fakeClientProvider) CreateClient(string) (client.Client, *rest.Config, error) {
+func (f fakeClientProvider) CreateClient(context.Context, string) (client.Client, *rest.Config, error) {
 	if f.client == nil || f.restConfig == nil {
 		return nil, nil, errors.New("fake client error")
 	}
@@ -536,7 +548,7 @@ var _ KubeConfigProvider = fakeKindConfigProvider{}
 type fakeKindConfigProvider struct{}
 
 // KubeConfig implements [kind.Provider].
-func (f fakeKindConfigProvider) KubeConfig(name string, localhost bool) (string, error) {
+func (f fakeKindConfigProvider) KubeConfig(_ context.Context, name string, localhost bool) (string, error) {
 	if localhost {
 		return minimalKubeconfig("https://127.0.0.1:12345"), nil
 	}
@@ -604,7 +616,7 @@ func accessRequest(name, namespace string, spec clustersv1alpha1.AccessRequestSp
 			Name:      name,
 			Namespace: namespace,
 			Labels: map[string]string{
-				"clusters.openmcp.cloud/provider": providerName,
+				"clusters.openmcp.cloud/provider": "kind",
 				"clusters.openmcp.cloud/profile":  "test",
 			},
 		},
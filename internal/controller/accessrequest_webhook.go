@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+)
+
+// AccessRequestWebhook validates the AccessRequests the provider is responsible for.
+type AccessRequestWebhook struct {
+	Config Config
+}
+
+// +kubebuilder:webhook:path=/validate-clusters-openmcp-cloud-v1alpha1-accessrequest,mutating=false,failurePolicy=fail,sideEffects=None,groups=clusters.openmcp.cloud,resources=accessrequests,verbs=create;update,versions=v1alpha1,name=vaccessrequest-v1alpha1.kind.clusters.openmcp.cloud,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the validating webhook for AccessRequests with the Manager.
+func (w *AccessRequestWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &clustersv1alpha1.AccessRequest{}).
+		WithValidator(w).
+		Complete()
+}
+
+// ValidateCreate rejects AccessRequests with invalid role references.
+func (w *AccessRequestWebhook) ValidateCreate(_ context.Context, ar *clustersv1alpha1.AccessRequest) (admission.Warnings, error) {
+	if !w.isResponsible(ar) {
+		return nil, nil
+	}
+	return nil, invalidAccessRequest(ar, validateAccessRequest(ar))
+}
+
+// ValidateUpdate validates the AccessRequest if its spec has been changed, so that existing AccessRequests can still be
+// updated, e.g. to remove their finalizers.
+func (w *AccessRequestWebhook) ValidateUpdate(_ context.Context, oldAR, ar *clustersv1alpha1.AccessRequest) (admission.Warnings, error) {
+	if !w.isResponsible(ar) {
+		return nil, nil
+	}
+	if w.isResponsible(oldAR) && equality.Semantic.DeepEqual(oldAR.Spec, ar.Spec) {
+		return nil, nil
+	}
+	return nil, invalidAccessRequest(ar, validateAccessRequest(ar))
+}
+
+// ValidateDelete implements admission.Validator.
+func (w *AccessRequestWebhook) ValidateDelete(context.Context, *clustersv1alpha1.AccessRequest) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// isResponsible returns true if the AccessRequest is labeled with the provider. Unlike
+// libutils.IsClusterProviderResponsibleForAccessRequest, it does not check the phase, which is not set during admission.
+func (w *AccessRequestWebhook) isResponsible(ar *clustersv1alpha1.AccessRequest) bool {
+	return ar.Labels[clustersv1alpha1.ProviderLabel] == w.Config.ProviderName
+}
+
+// validateAccessRequest checks that the role references are Roles with a namespace or ClusterRoles without one.
+// Role references of other kinds would otherwise be bound as ClusterRoles.
+func validateAccessRequest(ar *clustersv1alpha1.AccessRequest) field.ErrorList {
+	var errs field.ErrorList
+	if ar.Spec.Token == nil {
+		return errs
+	}
+
+	roleRefsPath := field.NewPath("spec", "token", "roleRefs")
+	for i, roleRef := range ar.Spec.Token.RoleRefs {
+		path := roleRefsPath.Index(i)
+		switch roleRef.Kind {
+		case kindRole:
+			if roleRef.Namespace == "" {
+				errs = append(errs, field.Required(path.Child("namespace"), "namespace is required for Roles"))
+			}
+		case kindClusterRole:
+			if roleRef.Namespace != "" {
+				errs = append(errs, field.Forbidden(path.Child("namespace"), "namespace must not be set for ClusterRoles"))
+			}
+		default:
+			errs = append(errs, field.NotSupported(path.Child("kind"), roleRef.Kind, []string{kindRole, kindClusterRole}))
+		}
+	}
+	return errs
+}
+
+func invalidAccessRequest(ar *clustersv1alpha1.AccessRequest, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(clustersv1alpha1.GroupVersion.WithKind("AccessRequest").GroupKind(), ar.Name, errs)
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	commonapi "github.com/openmcp-project/openmcp-operator/api/common"
+)
+
+func TestAccessRequestWebhook_ValidateCreate(t *testing.T) {
+	responsible := map[string]string{
+		clustersv1alpha1.ProviderLabel: "kind",
+		clustersv1alpha1.ProfileLabel:  "kind",
+	}
+
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		roleRefs []commonapi.RoleRef
+		wantErr  bool
+	}{
+		{
+			name:   "valid role references",
+			labels: responsible,
+			roleRefs: []commonapi.RoleRef{
+				{Kind: "Role", Name: "view", Namespace: "default"},
+				{Kind: "ClusterRole", Name: "cluster-admin"},
+			},
+		},
+		{
+			name:     "unsupported kind",
+			labels:   responsible,
+			roleRefs: []commonapi.RoleRef{{Kind: "Group", Name: "admins"}},
+			wantErr:  true,
+		},
+		{
+			name:     "role without namespace",
+			labels:   responsible,
+			roleRefs: []commonapi.RoleRef{{Kind: "Role", Name: "view"}},
+			wantErr:  true,
+		},
+		{
+			name:     "cluster role with namespace",
+			labels:   responsible,
+			roleRefs: []commonapi.RoleRef{{Kind: "ClusterRole", Name: "view", Namespace: "default"}},
+			wantErr:  true,
+		},
+		{
+			name:     "access request of another provider",
+			labels:   map[string]string{clustersv1alpha1.ProviderLabel: "gardener", clustersv1alpha1.ProfileLabel: "gardener"},
+			roleRefs: []commonapi.RoleRef{{Kind: "Group", Name: "admins"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &AccessRequestWebhook{Config: Config{ProviderName: "kind"}}
+			ar := &clustersv1alpha1.AccessRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Labels: tt.labels},
+				Spec: clustersv1alpha1.AccessRequestSpec{
+					Token: &clustersv1alpha1.TokenConfig{RoleRefs: tt.roleRefs},
+				},
+			}
+
+			_, err := w.ValidateCreate(context.Background(), ar)
+			if tt.wantErr {
+				assert.True(t, apierrors.IsInvalid(err), "expected invalid error, got %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Unchanged AccessRequests can be updated in any case.
+			_, err = w.ValidateUpdate(context.Background(), ar, ar.DeepCopy())
+			assert.NoError(t, err)
+		})
+	}
+}
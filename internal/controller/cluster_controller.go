@@ -18,14 +18,21 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"net"
+	"slices"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -37,8 +44,15 @@ import (
 	"github.com/openmcp-project/controller-utils/pkg/controller/smartrequeue"
 
 	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/bundle"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/cni"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/connectivity"
 	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
 	"github.com/openmcp-project/cluster-provider-kind/pkg/metallb"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/metrics"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/preload"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/tracing"
 )
 
 var (
@@ -47,23 +61,51 @@ var (
 
 	// AnnotationName can be used to override the name of the kind cluster.
 	AnnotationName = v1alpha1.SchemeGroupVersion.Group + "/name"
+
+	// defaultAddons are the addons that are enabled if the ProviderConfig does not list any.
+	defaultAddons = []string{metallb.AddonName}
 )
 
 const (
-	profileKind = "kind"
+	// reasonAnnotationReverted is the event reason for manual changes of annotations that have been reverted.
+	reasonAnnotationReverted = "AnnotationReverted"
+
+	// ConditionDockerHostAssigned is the condition type that reports whether the cluster has been placed on a Docker host.
+	ConditionDockerHostAssigned = "DockerHostAssigned"
+	// ConditionSubnetAssigned is the condition type that reports whether the Docker network and the LoadBalancer subnet
+	// have been assigned to the cluster.
+	ConditionSubnetAssigned = "SubnetAssigned"
+	// ConditionKindReady is the condition type that reports whether the kind cluster exists.
+	ConditionKindReady = "KindReady"
+	// ConditionKubeconfigAvailable is the condition type that reports whether the kubeconfig of the kind cluster can be retrieved.
+	ConditionKubeconfigAvailable = "KubeconfigAvailable"
 )
 
 // ClusterReconciler reconciles a Cluster object
 type ClusterReconciler struct {
+	Config Config
 	client.Client
 	Scheme       *runtime.Scheme
 	RequeueStore *smartrequeue.Store
 	Provider     kind.Provider
+	Recorder     events.EventRecorder
+	Addons       []addon.Addon
+
+	errorEvents errorEvents
+	failures    failures
+
+	// allocateSubnet returns the next free LoadBalancer subnet in a Docker network. Defaults to kind.NextAvailableLBNetwork.
+	allocateSubnet func(ctx context.Context, c client.Client, network string) (net.IPNet, error)
+	// deleteNetwork removes a Docker network once it is not used anymore. Defaults to kind.DeleteNetwork.
+	deleteNetwork func(ctx context.Context, name string) error
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ClusterReconciler.Reconcile", tracing.AttrNamespace.String(req.Namespace), tracing.AttrName.String(req.Name))
+	defer func() { tracing.End(span, err) }()
+
 	log := logf.FromContext(ctx)
 	log.Info("Reconcile")
 	defer log.Info("Done")
@@ -73,7 +115,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	if !isClusterProviderResponsible(cluster) {
+	if !isClusterProviderResponsible(r.Config.ProviderName, cluster) {
 		return ctrl.Result{}, fmt.Errorf("profile '%s' is not supported by kind controller", cluster.Spec.Profile)
 	}
 
@@ -81,7 +123,6 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	ctx = smartrequeue.NewContext(ctx, r.RequeueStore.For(cluster))
 
 	var result ctrl.Result
-	var err error
 
 	if cluster.DeletionTimestamp.IsZero() {
 		result, err = r.handleCreateOrUpdate(ctx, cluster)
@@ -90,15 +131,54 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if err != nil {
-		return result, err
+		r.errorEvents.record(r.Recorder, cluster, err)
+		r.setFailed(cluster, err)
+	} else {
+		r.errorEvents.reset(cluster)
+		r.failures.reset(cluster)
 	}
 
+	// The status is persisted on errors as well, so that the conditions show why the cluster is not ready.
 	cluster.Status.ObservedGeneration = cluster.Generation
 	if !equality.Semantic.DeepEqual(prevStatus, cluster.Status) {
-		return result, r.Status().Update(ctx, cluster)
+		if updateErr := r.Status().Update(ctx, cluster); client.IgnoreNotFound(updateErr) != nil {
+			return result, errors.Join(err, updateErr)
+		}
 	}
 
-	return result, nil
+	return result, err
+}
+
+// setFailed marks the Cluster as not ready because of the given error.
+// After failureThreshold consecutive failures, a Cluster that is not being deleted is in phase Error.
+func (r *ClusterReconciler) setFailed(cluster *clustersv1alpha1.Cluster, err error) {
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               string(commonapi.StatusPhaseReady),
+		Status:             metav1.ConditionFalse,
+		Reason:             "ReconcileError",
+		Message:            err.Error(),
+		ObservedGeneration: cluster.Generation,
+	})
+	if r.failures.inc(cluster) >= failureThreshold && cluster.DeletionTimestamp.IsZero() {
+		cluster.Status.Phase = clustersv1alpha1.CLUSTER_PHASE_ERROR
+	}
+}
+
+// stageFailed sets the condition of the failed reconciliation stage to False and returns the error,
+// so that the reconciliation is retried.
+func stageFailed(ctx context.Context, cluster *clustersv1alpha1.Cluster, conditionType, reason string, err error) (ctrl.Result, error) {
+	setCondition(cluster, conditionType, metav1.ConditionFalse, reason, err.Error())
+	return smartrequeue.FromContext(ctx).ReturnError(err)
+}
+
+func setCondition(cluster *clustersv1alpha1.Cluster, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cluster.Generation,
+	})
 }
 
 func (r *ClusterReconciler) handleDelete(ctx context.Context, cluster *clustersv1alpha1.Cluster) (ctrl.Result, error) {
@@ -117,14 +197,35 @@ func (r *ClusterReconciler) handleDelete(ctx context.Context, cluster *clustersv
 		return requeue.IsProgressing()
 	}
 
+	if err := r.restoreAnnotations(ctx, cluster); err != nil {
+		return requeue.ReturnError(err)
+	}
 	name := kindName(cluster)
 
-	exists, err := r.Provider.ClusterExists(name)
+	ctx, err := withClusterDockerHost(ctx, r.Client, r.Config.ProviderName, cluster)
+	if err != nil {
+		return requeue.ReturnError(err)
+	}
+
+	exists, err := r.Provider.ClusterExists(ctx, name)
 	if err != nil {
 		return requeue.ReturnError(err)
 	}
 
 	if !exists {
+		// The addons are finalized once the kind cluster is gone, so that a failed finalization is retried.
+		if err := addon.Finalize(ctx, r.Addons, name); err != nil {
+			return requeue.ReturnError(err)
+		}
+
+		deleteNetwork := r.deleteNetwork
+		if deleteNetwork == nil {
+			deleteNetwork = kind.DeleteNetwork
+		}
+		if err := deleteNetwork(ctx, kind.NetworkFromCluster(cluster)); err != nil {
+			return requeue.ReturnError(err)
+		}
+
 		controllerutil.RemoveFinalizer(cluster, Finalizer)
 		if err := r.Update(ctx, cluster); err != nil {
 			return requeue.ReturnError(err)
@@ -132,9 +233,13 @@ func (r *ClusterReconciler) handleDelete(ctx context.Context, cluster *clustersv
 		return requeue.StopRequeue()
 	}
 
-	if err := r.Provider.DeleteCluster(name); err != nil {
+	start := time.Now()
+	err = r.Provider.DeleteCluster(ctx, name)
+	metrics.ObserveDuration(metrics.ClusterDeleteDuration, start, err)
+	if err != nil {
 		return requeue.ReturnError(err)
 	}
+	r.Recorder.Eventf(cluster, nil, corev1.EventTypeNormal, "ClusterDeleted", "DeleteCluster", "Deleted kind cluster %s", name)
 	return requeue.IsProgressing()
 }
 
@@ -154,145 +259,516 @@ func (r *ClusterReconciler) handleCreateOrUpdate(ctx context.Context, cluster *c
 
 	cluster.Status.Phase = commonapi.StatusPhaseProgressing
 
-	if err := r.assignSubnet(ctx, cluster); err != nil {
+	if err := r.restoreAnnotations(ctx, cluster); err != nil {
 		return requeue.ReturnError(err)
 	}
 
-	name := kindName(cluster)
+	pc, err := getProviderConfig(ctx, r.Client, clusterProviderConfig(r.Config.ProviderName, cluster))
+	if err != nil {
+		return requeue.ReturnError(err)
+	}
 
-	exists, err := r.Provider.ClusterExists(name)
+	enabledAddons, err := addon.Enabled(r.Addons, addonNames(pc))
 	if err != nil {
 		return requeue.ReturnError(err)
 	}
 
+	ctx, err = r.placeCluster(ctx, cluster, pc)
+	if err != nil {
+		return stageFailed(ctx, cluster, ConditionDockerHostAssigned, "PlacementFailed", err)
+	}
+
+	if err := r.assignNetwork(ctx, cluster, pc); err != nil {
+		return stageFailed(ctx, cluster, ConditionSubnetAssigned, "NetworkFailed", err)
+	}
+	network := kind.NetworkFromCluster(cluster)
+	if err := kind.EnsureNetwork(ctx, network); err != nil {
+		return stageFailed(ctx, cluster, ConditionSubnetAssigned, "NetworkFailed", err)
+	}
+
+	if err := r.assignSubnet(ctx, cluster); err != nil {
+		return stageFailed(ctx, cluster, ConditionSubnetAssigned, "SubnetAllocationFailed", err)
+	}
+	setCondition(cluster, ConditionSubnetAssigned, metav1.ConditionTrue, "SubnetAssigned",
+		fmt.Sprintf("Assigned subnet %s in network %s", cluster.Annotations[kind.AnnotationAssignedSubnet], network))
+
+	name := kindName(cluster)
+
+	exists, err := r.Provider.ClusterExists(ctx, name)
+	if err != nil {
+		return stageFailed(ctx, cluster, ConditionKindReady, "ClusterLookupFailed", err)
+	}
+
 	if !exists {
-		if err := r.Provider.CreateCluster(name); err != nil {
-			return requeue.ReturnError(err)
+		clusterCfg := addon.ClusterConfig(enabledAddons)
+		clusterCfg.Network = network
+		if err := r.assignHostPorts(ctx, cluster, &clusterCfg); err != nil {
+			return stageFailed(ctx, cluster, ConditionKindReady, "CreateFailed", err)
+		}
+		if err := applyNodeConfig(ctx, cluster, pc, &clusterCfg); err != nil {
+			return stageFailed(ctx, cluster, ConditionKindReady, "CreateFailed", err)
+		}
+		subnetsAssigned, err := connectivity.AssignSubnets(ctx, r.Client, cluster, pc)
+		if err != nil {
+			return stageFailed(ctx, cluster, ConditionKindReady, "CreateFailed", err)
 		}
+		changed := cni.Annotate(cluster, pc) || subnetsAssigned
+		cni.ConfigureCluster(cluster, &clusterCfg)
+		connectivity.ConfigureCluster(cluster, &clusterCfg)
+
+		configHash, err := r.Provider.ConfigHash(ctx, clusterCfg)
+		if err != nil {
+			return stageFailed(ctx, cluster, ConditionKindReady, "CreateFailed", err)
+		}
+		if cluster.Annotations[kind.AnnotationConfigHash] != configHash {
+			metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationConfigHash, configHash)
+			changed = true
+		}
+		if changed {
+			if err := r.update(ctx, cluster); err != nil {
+				return stageFailed(ctx, cluster, ConditionKindReady, "CreateFailed", err)
+			}
+		}
+
+		start := time.Now()
+		err = r.Provider.CreateCluster(ctx, name, clusterCfg)
+		metrics.ObserveDuration(metrics.ClusterCreateDuration, start, err)
+		if err != nil {
+			return stageFailed(ctx, cluster, ConditionKindReady, "CreateFailed", err)
+		}
+		r.Recorder.Eventf(cluster, nil, corev1.EventTypeNormal, "ClusterCreated", "CreateCluster", "Created kind cluster %s", name)
 
 		return requeue.IsProgressing()
 	}
-	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
-		Type:   string("KindReady"),
-		Status: metav1.ConditionTrue,
-		Reason: "ClusterExists",
-	})
+	setCondition(cluster, ConditionKindReady, metav1.ConditionTrue, "ClusterExists", "")
 
-	err = cluster.Status.SetProviderStatus(v1alpha1.ClusterStatus{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: v1alpha1.SchemeGroupVersion.String(),
-			Kind:       "ClusterStatus",
-		},
-		KindClusterName: name,
-	})
+	previousStatus, err := getProviderStatus(cluster)
 	if err != nil {
 		return requeue.ReturnError(err)
 	}
 
-	localhostKubeconfig, err := r.Provider.KubeConfig(name, true)
+	info, err := r.Provider.ClusterInfo(ctx, name)
 	if err != nil {
+		return stageFailed(ctx, cluster, ConditionKindReady, "ClusterInfoFailed", err)
+	}
+
+	providerStatus := v1alpha1.ClusterStatus{
+		KindClusterName:    name,
+		KindVersion:        kind.Version(),
+		ConfigHash:         cluster.Annotations[kind.AnnotationConfigHash],
+		NodeImage:          info.NodeImage,
+		KubernetesVersion:  info.KubernetesVersion,
+		Nodes:              nodeStatuses(info.Nodes),
+		DockerHost:         previousStatus.DockerHost,
+		Network:            network,
+		LoadBalancerSubnet: cluster.Annotations[kind.AnnotationAssignedSubnet],
+		MetalLBVersion:     previousStatus.MetalLBVersion,
+		Bundles:            previousStatus.Bundles,
+		PreloadedImages:    previousStatus.PreloadedImages,
+	}
+	if !info.CreationTimestamp.IsZero() {
+		providerStatus.CreationTimestamp = &metav1.Time{Time: info.CreationTimestamp}
+	}
+	if err := setProviderStatus(cluster, providerStatus); err != nil {
 		return requeue.ReturnError(err)
 	}
-	containerKubeconfig, err := r.Provider.KubeConfig(name, false)
+
+	localhostKubeconfig, err := r.Provider.KubeConfig(ctx, name, true)
+	if err != nil {
+		return stageFailed(ctx, cluster, ConditionKubeconfigAvailable, "KubeconfigFailed", err)
+	}
+	containerKubeconfig, err := r.Provider.KubeConfig(ctx, name, false)
 	if err != nil {
-		return requeue.ReturnError(err)
+		return stageFailed(ctx, cluster, ConditionKubeconfigAvailable, "KubeconfigFailed", err)
 	}
 
 	localhostCfg, err := clientcmd.RESTConfigFromKubeConfig([]byte(localhostKubeconfig))
 	if err != nil {
-		return requeue.ReturnError(err)
+		return stageFailed(ctx, cluster, ConditionKubeconfigAvailable, "KubeconfigFailed", err)
 	}
 	containerCfg, err := clientcmd.RESTConfigFromKubeConfig([]byte(containerKubeconfig))
 	if err != nil {
-		return requeue.ReturnError(err)
+		return stageFailed(ctx, cluster, ConditionKubeconfigAvailable, "KubeconfigFailed", err)
 	}
 
 	cluster.Status.Endpoints = clustersv1alpha1.Endpoints{}
 	cluster.Status.Endpoints.Set(clustersv1alpha1.APISERVER_ENDPOINT_EXTERNAL, localhostCfg.Host)
 	cluster.Status.Endpoints.Set(clustersv1alpha1.APISERVER_ENDPOINT_INTERNAL, containerCfg.Host)
 
+	hostPorts, err := kind.HostPortsFromCluster(cluster)
+	if err != nil {
+		return requeue.ReturnError(err)
+	}
+	for _, m := range hostPorts {
+		cluster.Status.Endpoints.Set(m.Name, m.URL(ctx))
+	}
+
 	var kindClient client.Client
-	if runsOnLocalHost() {
-		kindClient, err = client.New(localhostCfg, client.Options{Scheme: r.Scheme})
+	if r.Config.KindOnLocalHost {
+		kindClient, err = client.NewWithWatch(localhostCfg, client.Options{Scheme: r.Scheme})
 	} else {
-		kindClient, err = client.New(containerCfg, client.Options{Scheme: r.Scheme})
+		kindClient, err = client.NewWithWatch(containerCfg, client.Options{Scheme: r.Scheme})
 	}
 	if err != nil {
+		return stageFailed(ctx, cluster, ConditionKubeconfigAvailable, "KubeconfigFailed", err)
+	}
+	setCondition(cluster, ConditionKubeconfigAvailable, metav1.ConditionTrue, "KubeconfigAvailable", "")
+
+	ac := addon.Context{
+		Cluster:        cluster,
+		KindName:       name,
+		Client:         kindClient,
+		Config:         pc,
+		ProviderStatus: &providerStatus,
+		Recorder:       r.Recorder,
+	}
+
+	cniReady, err := cni.Reconcile(ctx, ac)
+	if err := errors.Join(err, setProviderStatus(cluster, providerStatus)); err != nil {
 		return requeue.ReturnError(err)
 	}
+	if !cniReady {
+		return requeue.IsProgressing()
+	}
 
-	cNet, err := kind.SubnetFromCluster(cluster)
-	if err != nil {
+	_, err = connectivity.Reconcile(ctx, r.Client, ac)
+	if err := errors.Join(err, setProviderStatus(cluster, providerStatus)); err != nil {
 		return requeue.ReturnError(err)
 	}
 
-	if err := metallb.Install(ctx, kindClient); err != nil {
+	// Images are preloaded before the addons are installed, so that they can use them.
+	// Failures are reported once the addons and bundles have been reconciled, since they do not block them.
+	imagesPreloaded, preloadErr := preload.Reconcile(ctx, r.Provider, ac, previousStatus.PreloadedImages)
+	if err := setProviderStatus(cluster, providerStatus); err != nil {
 		return requeue.ReturnError(err)
 	}
 
-	metallbReady, err := metallb.IsReady(ctx, kindClient)
-	if err != nil {
+	addonsReady, err := addon.Reconcile(ctx, ac, r.Addons, enabledAddons)
+	if err := errors.Join(err, setProviderStatus(cluster, providerStatus)); err != nil {
 		return requeue.ReturnError(err)
 	}
-	if !metallbReady {
+	if !addonsReady {
 		return requeue.IsProgressing()
 	}
-	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
-		Type:   "MetalLBReady",
-		Status: metav1.ConditionTrue,
-		Reason: "AllPodsReady",
-	})
 
-	if err := metallb.ConfigureSubnet(ctx, kindClient, *cNet); err != nil {
+	bundlesReady, err := bundle.Reconcile(ctx, r.Client, ac, previousStatus.Bundles)
+	if err := errors.Join(err, setProviderStatus(cluster, providerStatus)); err != nil {
 		return requeue.ReturnError(err)
 	}
+	if !bundlesReady {
+		return requeue.IsProgressing()
+	}
+
+	if !imagesPreloaded {
+		return requeue.ReturnError(preloadErr)
+	}
 
 	cluster.Status.Phase = commonapi.StatusPhaseReady
 	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
 		Type:   string(commonapi.StatusPhaseReady),
 		Status: metav1.ConditionTrue,
-		Reason: "ClusterAndMetalLBReady",
+		Reason: "ClusterAndAddonsReady",
 	})
 	return requeue.IsStable()
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := metrics.Register(metrics.NewClusterCollector(mgr.GetClient(), func(cluster *clustersv1alpha1.Cluster) bool {
+		return isClusterProviderResponsible(r.Config.ProviderName, cluster)
+	})); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&clustersv1alpha1.Cluster{}).
 		Named("cluster").
 		Complete(r)
 }
 
+// placeCluster places a new cluster on one of the Docker hosts of the ProviderConfig and records the host in the
+// provider status. It returns a context for the Docker host of the cluster.
+func (r *ClusterReconciler) placeCluster(ctx context.Context, cluster *clustersv1alpha1.Cluster, pc *v1alpha1.ProviderConfig) (context.Context, error) {
+	status, err := getProviderStatus(cluster)
+	if err != nil {
+		return ctx, err
+	}
+
+	// Clusters that have been created before hosts could be configured stay on the Docker daemon of the provider.
+	// The configuration hash is only set after a cluster has been placed.
+	if status.DockerHost == "" && status.KindClusterName == "" && cluster.Annotations[kind.AnnotationConfigHash] == "" {
+		clusters := &clustersv1alpha1.ClusterList{}
+		if err := r.List(ctx, clusters); err != nil {
+			return ctx, err
+		}
+		providerConfig := clusterProviderConfig(r.Config.ProviderName, cluster)
+		others := slices.DeleteFunc(clusters.Items, func(other clustersv1alpha1.Cluster) bool {
+			name, ok := profileProviderConfig(r.Config.ProviderName, other.Spec.Profile)
+			return !ok || name != providerConfig || other.UID == cluster.UID
+		})
+
+		status.DockerHost, err = placeCluster(pc, cluster, others)
+		if err != nil {
+			return ctx, err
+		}
+		if err := setProviderStatus(cluster, status); err != nil {
+			return ctx, err
+		}
+		// The host is persisted before the kind cluster is created, so that the cluster is not placed again.
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return ctx, err
+		}
+		r.Recorder.Eventf(cluster, nil, corev1.EventTypeNormal, ConditionDockerHostAssigned, "PlaceCluster", "Placed cluster on Docker host %s", status.DockerHost)
+	}
+
+	endpoint, err := dockerEndpoint(pc, status.DockerHost)
+	if err != nil {
+		return ctx, err
+	}
+	if status.DockerHost != "" {
+		setCondition(cluster, ConditionDockerHostAssigned, metav1.ConditionTrue, "DockerHostAssigned", fmt.Sprintf("Placed on Docker host %s", status.DockerHost))
+	}
+	return kind.WithDockerEndpoint(ctx, endpoint), nil
+}
+
+// update updates the Cluster and keeps the status of the current reconciliation,
+// which would otherwise be replaced by the persisted status in the response.
+func (r *ClusterReconciler) update(ctx context.Context, cluster *clustersv1alpha1.Cluster) error {
+	status := cluster.Status.DeepCopy()
+	err := r.Update(ctx, cluster)
+	cluster.Status = *status
+	return err
+}
+
+// restoreAnnotations reverts manual changes of the annotations that identify the kind cluster and its LoadBalancer
+// subnet, which would otherwise orphan the kind cluster or break the MetalLB configuration. The original values are
+// taken from the provider status. A Warning event is recorded for each reverted annotation.
+func (r *ClusterReconciler) restoreAnnotations(ctx context.Context, cluster *clustersv1alpha1.Cluster) error {
+	status, err := getProviderStatus(cluster)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	if status.KindClusterName != "" && kindName(cluster) != status.KindClusterName {
+		delete(cluster.Annotations, AnnotationName)
+		if kindName(cluster) != status.KindClusterName {
+			metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, AnnotationName, status.KindClusterName)
+		}
+		r.Recorder.Eventf(cluster, nil, corev1.EventTypeWarning, reasonAnnotationReverted, "RestoreAnnotations",
+			"Annotation %s must not be changed, restored kind cluster name %s", AnnotationName, status.KindClusterName)
+		changed = true
+	}
+	if status.LoadBalancerSubnet != "" && cluster.Annotations[kind.AnnotationAssignedSubnet] != status.LoadBalancerSubnet {
+		metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationAssignedSubnet, status.LoadBalancerSubnet)
+		r.Recorder.Eventf(cluster, nil, corev1.EventTypeWarning, reasonAnnotationReverted, "RestoreAnnotations",
+			"Annotation %s must not be changed, restored subnet %s", kind.AnnotationAssignedSubnet, status.LoadBalancerSubnet)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return r.update(ctx, cluster)
+}
+
+// assignNetwork stores the Docker network of a new cluster in an annotation, depending on the network isolation of the
+// ProviderConfig. Clusters that already have a subnet assigned keep their network.
+func (r *ClusterReconciler) assignNetwork(ctx context.Context, cluster *clustersv1alpha1.Cluster, pc *v1alpha1.ProviderConfig) error {
+	if _, ok := cluster.Annotations[kind.AnnotationAssignedSubnet]; ok {
+		return nil
+	}
+	if _, ok := cluster.Annotations[kind.AnnotationNetwork]; ok {
+		return nil
+	}
+
+	network := networkName(cluster, pc)
+	if network == kind.DefaultNetworkName {
+		return nil
+	}
+
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationNetwork, network)
+	return r.update(ctx, cluster)
+}
+
 func (r *ClusterReconciler) assignSubnet(ctx context.Context, cluster *clustersv1alpha1.Cluster) error {
 	_, ok := cluster.Annotations[kind.AnnotationAssignedSubnet]
 	if ok {
 		return nil
 	}
 
-	availableNet, err := kind.NextAvailableLBNetwork(ctx, r.Client)
+	allocate := r.allocateSubnet
+	if allocate == nil {
+		allocate = kind.NextAvailableLBNetwork
+	}
+	availableNet, err := allocate(ctx, r.Client, kind.NetworkFromCluster(cluster))
 	if err != nil {
 		return err
 	}
 
 	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationAssignedSubnet, availableNet.String())
-	return r.Update(ctx, cluster)
+	if err := r.update(ctx, cluster); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(cluster, nil, corev1.EventTypeNormal, "SubnetAssigned", "AssignSubnet", "Assigned LoadBalancer subnet %s in network %s", availableNet.String(), kind.NetworkFromCluster(cluster))
+	return nil
 }
 
-func kindName(cluster *clustersv1alpha1.Cluster) string {
-	if name, ok := cluster.Annotations[AnnotationName]; ok {
-		return name
+// assignHostPorts assigns host ports to the port mappings of the cluster configuration and stores them in an annotation.
+// Host ports that have already been assigned to the cluster are reused.
+func (r *ClusterReconciler) assignHostPorts(ctx context.Context, cluster *clustersv1alpha1.Cluster, cfg *kind.ClusterConfig) error {
+	if len(cfg.PortMappings) == 0 {
+		return nil
 	}
-	return fmt.Sprintf("%s.%s", cluster.Name, string(cluster.UID)[:8])
+
+	assigned, err := kind.HostPortsFromCluster(cluster)
+	if err != nil {
+		return err
+	}
+	if assigned != nil {
+		cfg.PortMappings = assigned
+		return nil
+	}
+
+	assigned, err = kind.AssignHostPorts(ctx, r.Client, cfg.PortMappings)
+	if err != nil {
+		return err
+	}
+
+	value, err := kind.EncodeHostPorts(assigned)
+	if err != nil {
+		return err
+	}
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationHostPorts, value)
+	if err := r.update(ctx, cluster); err != nil {
+		return err
+	}
+
+	cfg.PortMappings = assigned
+	return nil
+}
+
+// applyNodeConfig adds the registry mirrors, proxy and CA certificates of the ProviderConfig to the cluster configuration.
+// The kind network and the LoadBalancer subnet of the cluster are reached without the proxy.
+func applyNodeConfig(ctx context.Context, cluster *clustersv1alpha1.Cluster, pc *v1alpha1.ProviderConfig, cfg *kind.ClusterConfig) error {
+	noProxy := []string{}
+	if pc.Spec.Proxy != nil {
+		kindNet, err := kind.GetDockerV4Network(ctx, kind.NetworkFromCluster(cluster))
+		if err != nil {
+			return err
+		}
+		noProxy = append(noProxy, kindNet.String())
+
+		subnet, err := kind.SubnetFromCluster(cluster)
+		if err != nil {
+			return err
+		}
+		if subnet != nil {
+			noProxy = append(noProxy, subnet.String())
+		}
+	}
+
+	setNodeConfig(pc, noProxy, cfg)
+	return nil
+}
+
+func setNodeConfig(pc *v1alpha1.ProviderConfig, noProxy []string, cfg *kind.ClusterConfig) {
+	for _, m := range pc.Spec.RegistryMirrors {
+		cfg.RegistryMirrors = append(cfg.RegistryMirrors, kind.RegistryMirror{Registry: m.Registry, Endpoints: m.Endpoints})
+	}
+	cfg.CACertificates = append(cfg.CACertificates, pc.Spec.CACertificates...)
+	cfg.NodeImage = pc.Spec.NodeImage
+
+	if pc.Spec.Proxy != nil {
+		cfg.Proxy = &kind.ProxyConfig{
+			HTTPProxy:  pc.Spec.Proxy.HTTPProxy,
+			HTTPSProxy: pc.Spec.Proxy.HTTPSProxy,
+			NoProxy:    append(slices.Clone(pc.Spec.Proxy.NoProxy), noProxy...),
+		}
+	}
+}
+
+// networkName returns the name of the Docker network for the cluster, depending on the network isolation.
+func networkName(cluster *clustersv1alpha1.Cluster, pc *v1alpha1.ProviderConfig) string {
+	cfg := ptr.Deref(pc.Spec.Network, v1alpha1.NetworkConfig{})
+	switch cfg.Isolation {
+	case v1alpha1.NetworkIsolationCluster:
+		return "kind-" + kindName(cluster)
+	case v1alpha1.NetworkIsolationTenant:
+		return "kind-tenant-" + cluster.Namespace
+	default:
+		return kind.DefaultNetworkName
+	}
+}
+
+// kubernetesVersion returns the Kubernetes version of the clusters of the ProviderConfig, i.e. the version of its node
+// image or of the default node image of kind.
+func kubernetesVersion(pc *v1alpha1.ProviderConfig) string {
+	if pc.Spec.NodeImage != "" {
+		return kind.ImageKubernetesVersion(pc.Spec.NodeImage)
+	}
+	return kind.KubernetesVersion()
+}
+
+// getProviderConfig returns the ProviderConfig with the given name.
+// If it does not exist, an empty ProviderConfig is returned so that the defaults apply.
+func getProviderConfig(ctx context.Context, c client.Reader, name string) (*v1alpha1.ProviderConfig, error) {
+	pc := &v1alpha1.ProviderConfig{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, pc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		logf.FromContext(ctx).V(1).Info("ProviderConfig not found, using defaults", "name", name)
+		return &v1alpha1.ProviderConfig{}, nil
+	}
+	return pc, nil
+}
+
+// addonNames returns the names of the addons enabled in the ProviderConfig.
+func addonNames(pc *v1alpha1.ProviderConfig) []string {
+	if pc.Spec.Addons == nil {
+		return defaultAddons
+	}
+	return pc.Spec.Addons
 }
 
-func isClusterProviderResponsible(cluster *clustersv1alpha1.Cluster) bool {
-	return cluster.Spec.Profile == profileKind
+// nodeStatuses converts the node containers of a kind cluster into their provider status.
+func nodeStatuses(nodes []kind.Node) []v1alpha1.NodeStatus {
+	statuses := make([]v1alpha1.NodeStatus, 0, len(nodes))
+	for _, n := range nodes {
+		statuses = append(statuses, v1alpha1.NodeStatus{
+			Name:        n.Name,
+			ContainerID: n.ContainerID,
+			IP:          n.IP,
+			Role:        n.Role,
+		})
+	}
+	return statuses
+}
+
+// getProviderStatus returns the provider-specific status stored in the Cluster status.
+func getProviderStatus(cluster *clustersv1alpha1.Cluster) (v1alpha1.ClusterStatus, error) {
+	status := v1alpha1.ClusterStatus{}
+	if cluster.Status.ProviderStatus == nil || len(cluster.Status.ProviderStatus.Raw) == 0 {
+		return status, nil
+	}
+	err := cluster.Status.GetProviderStatus(&status)
+	return status, err
+}
+
+// setProviderStatus stores the given provider-specific status in the Cluster status.
+func setProviderStatus(cluster *clustersv1alpha1.Cluster, status v1alpha1.ClusterStatus) error {
+	status.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "ClusterStatus",
+	}
+	return cluster.Status.SetProviderStatus(status)
 }
 
-// runsOnLocalHost returns true if the KIND_ON_LOCAL_HOST environment variable is set to "true".
-func runsOnLocalHost() bool {
-	return os.Getenv("KIND_ON_LOCAL_HOST") == "true"
+func kindName(cluster *clustersv1alpha1.Cluster) string {
+	if name, ok := cluster.Annotations[AnnotationName]; ok {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", cluster.Name, string(cluster.UID)[:8])
 }
 
 // identifyFinalizers checks two things for the given object:
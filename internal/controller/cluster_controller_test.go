@@ -0,0 +1,612 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	commonapi "github.com/openmcp-project/openmcp-operator/api/common"
+
+	"github.com/openmcp-project/controller-utils/pkg/controller/smartrequeue"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/metallb"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/tracing"
+)
+
+func Test_setNodeConfig(t *testing.T) {
+	pc := &v1alpha1.ProviderConfig{
+		Spec: v1alpha1.ProviderConfigSpec{
+			RegistryMirrors: []v1alpha1.RegistryMirror{{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}}},
+			Proxy: &v1alpha1.ProxyConfig{
+				HTTPProxy:  "http://proxy.example.com:3128",
+				HTTPSProxy: "http://proxy.example.com:3128",
+				NoProxy:    []string{"example.com"},
+			},
+			CACertificates: []string{"-----BEGIN CERTIFICATE-----"},
+			NodeImage:      "kindest/node:v1.33.1",
+		},
+	}
+
+	cfg := kind.ClusterConfig{}
+	setNodeConfig(pc, []string{"172.18.0.0/16", "172.18.200.0/24"}, &cfg)
+
+	assert.Equal(t, []kind.RegistryMirror{{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}}}, cfg.RegistryMirrors)
+	assert.Equal(t, []string{"-----BEGIN CERTIFICATE-----"}, cfg.CACertificates)
+	assert.Equal(t, "kindest/node:v1.33.1", cfg.NodeImage)
+	assert.Equal(t, &kind.ProxyConfig{
+		HTTPProxy:  "http://proxy.example.com:3128",
+		HTTPSProxy: "http://proxy.example.com:3128",
+		NoProxy:    []string{"example.com", "172.18.0.0/16", "172.18.200.0/24"},
+	}, cfg.Proxy)
+	assert.Equal(t, []string{"example.com"}, pc.Spec.Proxy.NoProxy)
+}
+
+func Test_networkName(t *testing.T) {
+	cluster := &clustersv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "one", Namespace: "tenant-a", UID: "6f2a311e-ac05-dd15-9140-c280f38b28f4"},
+	}
+
+	testCases := []struct {
+		desc      string
+		isolation v1alpha1.NetworkIsolation
+		expected  string
+	}{
+		{
+			desc:      "should use kind network without isolation",
+			isolation: "",
+			expected:  kind.DefaultNetworkName,
+		},
+		{
+			desc:      "should use network per cluster",
+			isolation: v1alpha1.NetworkIsolationCluster,
+			expected:  "kind-one.6f2a311e",
+		},
+		{
+			desc:      "should use network per namespace",
+			isolation: v1alpha1.NetworkIsolationTenant,
+			expected:  "kind-tenant-tenant-a",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			pc := &v1alpha1.ProviderConfig{Spec: v1alpha1.ProviderConfigSpec{Network: &v1alpha1.NetworkConfig{Isolation: tC.isolation}}}
+			assert.Equal(t, tC.expected, networkName(cluster, pc))
+		})
+	}
+}
+
+// fakeProvider is a kind.Provider for existing clusters. Its stages fail with the configured errors.
+type fakeProvider struct {
+	kind.Provider
+	info kind.ClusterInfo
+
+	missing       bool
+	existsErr     error
+	createErr     error
+	kubeconfigErr error
+}
+
+func (p *fakeProvider) ClusterExists(context.Context, string) (bool, error) {
+	return !p.missing, p.existsErr
+}
+
+func (p *fakeProvider) ConfigHash(context.Context, kind.ClusterConfig) (string, error) {
+	return "0123456789abcdef", nil
+}
+
+func (p *fakeProvider) CreateCluster(context.Context, string, kind.ClusterConfig) error {
+	return p.createErr
+}
+
+func (p *fakeProvider) KubeConfig(_ context.Context, _ string, localhost bool) (string, error) {
+	if p.kubeconfigErr != nil {
+		return "", p.kubeconfigErr
+	}
+	if localhost {
+		return minimalKubeconfig("https://127.0.0.1:12345"), nil
+	}
+	return minimalKubeconfig("https://172.18.0.3:6443"), nil
+}
+
+func (p *fakeProvider) ClusterInfo(context.Context, string) (kind.ClusterInfo, error) {
+	return p.info, nil
+}
+
+// fakeAddon is an addon that is always ready, unless it fails to install or to be configured.
+type fakeAddon struct {
+	name         string
+	installErr   error
+	configureErr error
+}
+
+func (a fakeAddon) Name() string {
+	return a.name
+}
+
+func (a fakeAddon) Install(_ context.Context, _ addon.Context) error {
+	return a.installErr
+}
+
+func (a fakeAddon) Ready(_ context.Context, _ addon.Context) (bool, error) {
+	return true, nil
+}
+
+func (a fakeAddon) Configure(_ context.Context, _ addon.Context) error {
+	return a.configureErr
+}
+
+func (a fakeAddon) Uninstall(_ context.Context, _ addon.Context) error {
+	return nil
+}
+
+// finalizingAddon is a fakeAddon that fails to be finalized with the configured errors, one per call.
+type finalizingAddon struct {
+	fakeAddon
+	finalizeErrs []error
+	finalized    int
+}
+
+func (a *finalizingAddon) Finalize(context.Context, string) error {
+	a.finalized++
+	if len(a.finalizeErrs) == 0 {
+		return nil
+	}
+	err := a.finalizeErrs[0]
+	a.finalizeErrs = a.finalizeErrs[1:]
+	return err
+}
+
+func TestClusterReconciler_retriesFinalize(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	errFailed := errors.New("failed")
+	cluster := &clustersv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test",
+			Namespace:         "default",
+			Finalizers:        []string{Finalizer},
+			DeletionTimestamp: ptr.To(metav1.Now()),
+			Annotations:       map[string]string{AnnotationName: "test"},
+		},
+		Spec: clustersv1alpha1.ClusterSpec{Profile: profileKind},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).WithStatusSubresource(cluster).Build()
+
+	a := &finalizingAddon{fakeAddon: fakeAddon{name: metallb.AddonName}, finalizeErrs: []error{errFailed}}
+	deletedNetworks := []string{}
+	r := &ClusterReconciler{
+		Client:       c,
+		Scheme:       scheme,
+		RequeueStore: smartrequeue.NewStore(time.Second, time.Minute, 2),
+		Recorder:     events.NewFakeRecorder(10),
+		Addons:       []addon.Addon{a},
+		// the kind cluster has already been deleted by an earlier reconciliation
+		Provider: &fakeProvider{missing: true},
+		deleteNetwork: func(_ context.Context, name string) error {
+			deletedNetworks = append(deletedNetworks, name)
+			return nil
+		},
+	}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	assert.ErrorIs(t, err, errFailed)
+	assert.Equal(t, 1, a.finalized)
+	assert.Empty(t, deletedNetworks)
+
+	actual := &clustersv1alpha1.Cluster{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), actual))
+	assert.Contains(t, actual.Finalizers, Finalizer)
+
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, a.finalized)
+	assert.Equal(t, []string{kind.DefaultNetworkName}, deletedNetworks)
+
+	err = c.Get(context.Background(), client.ObjectKeyFromObject(cluster), actual)
+	assert.True(t, apierrors.IsNotFound(err), "expected the cluster to be deleted, got %v", err)
+}
+
+func TestClusterReconciler_providerStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cluster := &clustersv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Namespace:  "default",
+			Finalizers: []string{Finalizer},
+			Annotations: map[string]string{
+				AnnotationName:                "test",
+				kind.AnnotationAssignedSubnet: "172.18.200.0/24",
+				kind.AnnotationConfigHash:     "0123456789abcdef",
+			},
+		},
+		Spec: clustersv1alpha1.ClusterSpec{Profile: profileKind},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).WithStatusSubresource(cluster).Build()
+	recorder := events.NewFakeRecorder(10)
+	spans := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+
+	r := &ClusterReconciler{
+		Client:       c,
+		Scheme:       scheme,
+		RequeueStore: smartrequeue.NewStore(time.Second, time.Minute, 2),
+		Recorder:     recorder,
+		Addons:       []addon.Addon{fakeAddon{name: metallb.AddonName}},
+		Provider: &fakeProvider{info: kind.ClusterInfo{
+			Nodes: []kind.Node{
+				{Name: "test-control-plane", ContainerID: "abc123", IP: "172.18.0.3", Role: "control-plane"},
+				{Name: "test-worker", ContainerID: "def456", IP: "172.18.0.4", Role: "worker"},
+			},
+			NodeImage:         "kindest/node:v1.33.1",
+			KubernetesVersion: "v1.33.1",
+			CreationTimestamp: created,
+		}},
+	}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	assert.NoError(t, err)
+
+	actual := &clustersv1alpha1.Cluster{}
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), actual))
+	status := v1alpha1.ClusterStatus{}
+	assert.NoError(t, actual.Status.GetProviderStatus(&status))
+
+	assert.Equal(t, "test", status.KindClusterName)
+	assert.Equal(t, kind.Version(), status.KindVersion)
+	assert.Equal(t, "0123456789abcdef", status.ConfigHash)
+	assert.Equal(t, created, status.CreationTimestamp.UTC())
+	assert.Equal(t, "kindest/node:v1.33.1", status.NodeImage)
+	assert.Equal(t, "v1.33.1", status.KubernetesVersion)
+	assert.Equal(t, []v1alpha1.NodeStatus{
+		{Name: "test-control-plane", ContainerID: "abc123", IP: "172.18.0.3", Role: "control-plane"},
+		{Name: "test-worker", ContainerID: "def456", IP: "172.18.0.4", Role: "worker"},
+	}, status.Nodes)
+	assert.Equal(t, kind.DefaultNetworkName, status.Network)
+	assert.Equal(t, "172.18.200.0/24", status.LoadBalancerSubnet)
+	assert.Equal(t, commonapi.StatusPhaseReady, actual.Status.Phase)
+	assert.Equal(t, []string{"MetalLBReady"}, eventReasons(recorder))
+	assert.Len(t, spans.Ended(), 1)
+	assert.Equal(t, "ClusterReconciler.Reconcile", spans.Ended()[0].Name())
+	assert.Contains(t, spans.Ended()[0].Attributes(), tracing.AttrName.String("test"))
+
+	// Requeues do not repeat the events.
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	assert.NoError(t, err)
+	assert.Empty(t, eventReasons(recorder))
+}
+
+func TestClusterReconciler_placeCluster(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	pc := &v1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: providerConfigName},
+		Spec: v1alpha1.ProviderConfigSpec{DockerHosts: []v1alpha1.DockerHost{
+			{Name: "one", Host: "unix:///var/run/docker.sock"},
+			{Name: "two", Host: "tcp://two.example.com:2376", TLSCertPath: "/etc/docker/two"},
+		}},
+	}
+	placed := &clustersv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "placed", Namespace: "default", UID: "0d9c5b1e-1e4f-4c55-a0b1-5c9f2a0e7d11"},
+		Spec:       clustersv1alpha1.ClusterSpec{Profile: ProfileName("kind", providerConfigName)},
+	}
+	require.NoError(t, setProviderStatus(placed, v1alpha1.ClusterStatus{DockerHost: "one"}))
+	cluster := &clustersv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Namespace:  "default",
+			UID:        "6f2a311e-ac05-dd15-9140-c280f38b28f4",
+			Finalizers: []string{Finalizer},
+			Annotations: map[string]string{
+				AnnotationName:                "test",
+				kind.AnnotationAssignedSubnet: "172.18.200.0/24",
+			},
+		},
+		Spec: clustersv1alpha1.ClusterSpec{Profile: profileKind},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pc, placed, cluster).WithStatusSubresource(placed, cluster).Build()
+	recorder := events.NewFakeRecorder(10)
+	r := &ClusterReconciler{
+		Config:       Config{ProviderName: "kind"},
+		Client:       c,
+		Scheme:       scheme,
+		RequeueStore: smartrequeue.NewStore(time.Second, time.Minute, 2),
+		Recorder:     recorder,
+		Addons:       []addon.Addon{fakeAddon{name: metallb.AddonName}},
+		Provider:     &fakeProvider{},
+	}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	assert.NoError(t, err)
+
+	actual := &clustersv1alpha1.Cluster{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), actual))
+	status, err := getProviderStatus(actual)
+	require.NoError(t, err)
+	assert.Equal(t, "two", status.DockerHost)
+	assert.True(t, meta.IsStatusConditionTrue(actual.Status.Conditions, ConditionDockerHostAssigned))
+	assert.Equal(t, []string{"DockerHostAssigned", "MetalLBReady"}, eventReasons(recorder))
+
+	// The cluster stays on its host, even if the load changes.
+	require.NoError(t, setProviderStatus(placed, v1alpha1.ClusterStatus{DockerHost: "two"}))
+	require.NoError(t, c.Status().Update(context.Background(), placed))
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	assert.NoError(t, err)
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), actual))
+	status, err = getProviderStatus(actual)
+	require.NoError(t, err)
+	assert.Equal(t, "two", status.DockerHost)
+	assert.Empty(t, eventReasons(recorder))
+}
+
+func TestClusterReconciler_failureConditions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	errFailed := errors.New("failed")
+
+	testCases := []struct {
+		desc           string
+		unassigned     bool
+		provider       *fakeProvider
+		addon          fakeAddon
+		expectedType   string
+		expectedReason string
+	}{
+		{
+			desc:           "should report failed subnet allocation",
+			unassigned:     true,
+			provider:       &fakeProvider{},
+			addon:          fakeAddon{name: metallb.AddonName},
+			expectedType:   ConditionSubnetAssigned,
+			expectedReason: "SubnetAllocationFailed",
+		},
+		{
+			desc:           "should report failed cluster lookup",
+			provider:       &fakeProvider{existsErr: errFailed},
+			addon:          fakeAddon{name: metallb.AddonName},
+			expectedType:   ConditionKindReady,
+			expectedReason: "ClusterLookupFailed",
+		},
+		{
+			desc:           "should report failed kind create",
+			provider:       &fakeProvider{missing: true, createErr: errFailed},
+			addon:          fakeAddon{name: metallb.AddonName},
+			expectedType:   ConditionKindReady,
+			expectedReason: "CreateFailed",
+		},
+		{
+			desc:           "should report failed kubeconfig fetch",
+			provider:       &fakeProvider{kubeconfigErr: errFailed},
+			addon:          fakeAddon{name: metallb.AddonName},
+			expectedType:   ConditionKubeconfigAvailable,
+			expectedReason: "KubeconfigFailed",
+		},
+		{
+			desc:           "should report failed MetalLB install",
+			provider:       &fakeProvider{},
+			addon:          fakeAddon{name: metallb.AddonName, installErr: errFailed},
+			expectedType:   "MetalLBReady",
+			expectedReason: "InstallFailed",
+		},
+		{
+			desc:           "should report failed MetalLB configuration",
+			provider:       &fakeProvider{},
+			addon:          fakeAddon{name: metallb.AddonName, configureErr: errFailed},
+			expectedType:   "MetalLBReady",
+			expectedReason: "ConfigurationFailed",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cluster := &clustersv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test",
+					Namespace:   "default",
+					UID:         "6f2a311e-ac05-dd15-9140-c280f38b28f4",
+					Finalizers:  []string{Finalizer},
+					Annotations: map[string]string{AnnotationName: "test"},
+				},
+				Spec: clustersv1alpha1.ClusterSpec{Profile: profileKind},
+			}
+			if !tC.unassigned {
+				cluster.Annotations[kind.AnnotationAssignedSubnet] = "172.18.200.0/24"
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).WithStatusSubresource(cluster).Build()
+
+			r := &ClusterReconciler{
+				Client:       c,
+				Scheme:       scheme,
+				RequeueStore: smartrequeue.NewStore(time.Second, time.Minute, 2),
+				Recorder:     events.NewFakeRecorder(10),
+				Addons:       []addon.Addon{tC.addon},
+				Provider:     tC.provider,
+				allocateSubnet: func(context.Context, client.Client, string) (net.IPNet, error) {
+					return net.IPNet{}, errFailed
+				},
+			}
+
+			for i := 1; i <= failureThreshold; i++ {
+				_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+				assert.ErrorIs(t, err, errFailed)
+
+				actual := &clustersv1alpha1.Cluster{}
+				assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), actual))
+
+				condition := meta.FindStatusCondition(actual.Status.Conditions, tC.expectedType)
+				if assert.NotNil(t, condition) {
+					assert.Equal(t, metav1.ConditionFalse, condition.Status)
+					assert.Equal(t, tC.expectedReason, condition.Reason)
+					assert.Contains(t, condition.Message, errFailed.Error())
+				}
+				assert.True(t, meta.IsStatusConditionFalse(actual.Status.Conditions, string(commonapi.StatusPhaseReady)))
+				if i < failureThreshold {
+					assert.NotEqual(t, clustersv1alpha1.CLUSTER_PHASE_ERROR, actual.Status.Phase)
+				} else {
+					assert.Equal(t, clustersv1alpha1.CLUSTER_PHASE_ERROR, actual.Status.Phase)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterReconciler_recoversFromFailures(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	cluster := &clustersv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Namespace:  "default",
+			UID:        "6f2a311e-ac05-dd15-9140-c280f38b28f4",
+			Finalizers: []string{Finalizer},
+			Annotations: map[string]string{
+				AnnotationName:                "test",
+				kind.AnnotationAssignedSubnet: "172.18.200.0/24",
+			},
+		},
+		Spec: clustersv1alpha1.ClusterSpec{Profile: profileKind},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).WithStatusSubresource(cluster).Build()
+	provider := &fakeProvider{kubeconfigErr: errors.New("failed")}
+	r := &ClusterReconciler{
+		Client:       c,
+		Scheme:       scheme,
+		RequeueStore: smartrequeue.NewStore(time.Second, time.Minute, 2),
+		Recorder:     events.NewFakeRecorder(10),
+		Addons:       []addon.Addon{fakeAddon{name: metallb.AddonName}},
+		Provider:     provider,
+	}
+
+	for range failureThreshold {
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+		assert.Error(t, err)
+	}
+
+	provider.kubeconfigErr = nil
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	assert.NoError(t, err)
+
+	actual := &clustersv1alpha1.Cluster{}
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), actual))
+	assert.Equal(t, commonapi.StatusPhaseReady, actual.Status.Phase)
+	assert.True(t, meta.IsStatusConditionTrue(actual.Status.Conditions, ConditionKubeconfigAvailable))
+	assert.True(t, meta.IsStatusConditionTrue(actual.Status.Conditions, ConditionKindReady))
+	assert.True(t, meta.IsStatusConditionTrue(actual.Status.Conditions, ConditionSubnetAssigned))
+
+	// A single failure after the recovery does not put the cluster into phase Error again.
+	provider.kubeconfigErr = errors.New("failed")
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	assert.Error(t, err)
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), actual))
+	assert.NotEqual(t, clustersv1alpha1.CLUSTER_PHASE_ERROR, actual.Status.Phase)
+}
+
+func TestClusterReconciler_restoreAnnotations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	testCases := []struct {
+		desc        string
+		status      v1alpha1.ClusterStatus
+		annotations map[string]string
+		expected    map[string]string
+		events      []string
+	}{
+		{
+			desc:        "should keep unchanged annotations",
+			status:      v1alpha1.ClusterStatus{KindClusterName: "test", LoadBalancerSubnet: "172.18.200.0/24"},
+			annotations: map[string]string{AnnotationName: "test", kind.AnnotationAssignedSubnet: "172.18.200.0/24"},
+			expected:    map[string]string{AnnotationName: "test", kind.AnnotationAssignedSubnet: "172.18.200.0/24"},
+		},
+		{
+			desc:        "should restore changed annotations",
+			status:      v1alpha1.ClusterStatus{KindClusterName: "test", LoadBalancerSubnet: "172.18.200.0/24"},
+			annotations: map[string]string{AnnotationName: "other", kind.AnnotationAssignedSubnet: "172.18.201.0/24"},
+			expected:    map[string]string{AnnotationName: "test", kind.AnnotationAssignedSubnet: "172.18.200.0/24"},
+			events:      []string{reasonAnnotationReverted, reasonAnnotationReverted},
+		},
+		{
+			desc:        "should restore removed annotations",
+			status:      v1alpha1.ClusterStatus{KindClusterName: "test", LoadBalancerSubnet: "172.18.200.0/24"},
+			annotations: nil,
+			expected:    map[string]string{AnnotationName: "test", kind.AnnotationAssignedSubnet: "172.18.200.0/24"},
+			events:      []string{reasonAnnotationReverted, reasonAnnotationReverted},
+		},
+		{
+			desc:        "should remove an added name",
+			status:      v1alpha1.ClusterStatus{KindClusterName: "test.6f2a311e", LoadBalancerSubnet: "172.18.200.0/24"},
+			annotations: map[string]string{AnnotationName: "other", kind.AnnotationAssignedSubnet: "172.18.200.0/24"},
+			expected:    map[string]string{kind.AnnotationAssignedSubnet: "172.18.200.0/24"},
+			events:      []string{reasonAnnotationReverted},
+		},
+		{
+			desc:        "should not touch new clusters",
+			annotations: map[string]string{AnnotationName: "other"},
+			expected:    map[string]string{AnnotationName: "other"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cluster := &clustersv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test",
+					Namespace:   "default",
+					UID:         "6f2a311e-ac05-dd15-9140-c280f38b28f4",
+					Annotations: tC.annotations,
+				},
+			}
+			if tC.status.KindClusterName != "" {
+				assert.NoError(t, setProviderStatus(cluster, tC.status))
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+			recorder := events.NewFakeRecorder(10)
+			r := &ClusterReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+			assert.NoError(t, r.restoreAnnotations(context.Background(), cluster))
+
+			actual := &clustersv1alpha1.Cluster{}
+			assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(cluster), actual))
+			assert.Equal(t, tC.expected, actual.Annotations)
+			assert.Equal(t, tC.events, eventReasons(recorder))
+		})
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+// nodeNameSuffix is the longest suffix kind appends to the cluster name for the names and hostnames of the node containers.
+const nodeNameSuffix = "-control-plane"
+
+// ClusterWebhook defaults and validates Clusters with the profiles of the provider. Clusters of other profiles are not touched.
+type ClusterWebhook struct {
+	Config Config
+	Client client.Client
+}
+
+// +kubebuilder:webhook:path=/mutate-clusters-openmcp-cloud-v1alpha1-cluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=clusters.openmcp.cloud,resources=clusters,verbs=create,versions=v1alpha1,name=mcluster-v1alpha1.kind.clusters.openmcp.cloud,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-clusters-openmcp-cloud-v1alpha1-cluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=clusters.openmcp.cloud,resources=clusters,verbs=create;update,versions=v1alpha1,name=vcluster-v1alpha1.kind.clusters.openmcp.cloud,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the defaulting and validating webhooks for Clusters with the Manager.
+func (w *ClusterWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &clustersv1alpha1.Cluster{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// Default stores the Docker network of a new cluster in an annotation, depending on the network isolation of the
+// ProviderConfig. Networks per cluster depend on the UID of the Cluster, which is not known yet during admission,
+// unless the name of the kind cluster is set explicitly; they are assigned by the controller otherwise.
+func (w *ClusterWebhook) Default(ctx context.Context, cluster *clustersv1alpha1.Cluster) error {
+	if !isClusterProviderResponsible(w.Config.ProviderName, cluster) {
+		return nil
+	}
+	if _, ok := cluster.Annotations[kind.AnnotationAssignedSubnet]; ok {
+		return nil
+	}
+	if _, ok := cluster.Annotations[kind.AnnotationNetwork]; ok {
+		return nil
+	}
+
+	pc, err := getProviderConfig(ctx, w.Client, clusterProviderConfig(w.Config.ProviderName, cluster))
+	if err != nil {
+		return err
+	}
+	if _, ok := cluster.Annotations[AnnotationName]; !ok && ptr.Deref(pc.Spec.Network, v1alpha1.NetworkConfig{}).Isolation == v1alpha1.NetworkIsolationCluster {
+		return nil
+	}
+
+	network := networkName(cluster, pc)
+	if network == kind.DefaultNetworkName {
+		return nil
+	}
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationNetwork, network)
+	return nil
+}
+
+// ValidateCreate rejects clusters with an unsupported Kubernetes version, a name that is no valid kind cluster name or
+// malformed annotations.
+func (w *ClusterWebhook) ValidateCreate(ctx context.Context, cluster *clustersv1alpha1.Cluster) (admission.Warnings, error) {
+	if !isClusterProviderResponsible(w.Config.ProviderName, cluster) {
+		return nil, nil
+	}
+	supported, err := w.supportedVersion(ctx, nil, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return nil, invalidCluster(cluster, validateCluster(nil, cluster, supported))
+}
+
+// ValidateUpdate validates the fields that have been changed, so that existing clusters can still be updated, e.g. to
+// remove their finalizers.
+func (w *ClusterWebhook) ValidateUpdate(ctx context.Context, oldCluster, cluster *clustersv1alpha1.Cluster) (admission.Warnings, error) {
+	if !isClusterProviderResponsible(w.Config.ProviderName, cluster) {
+		return nil, nil
+	}
+	supported, err := w.supportedVersion(ctx, oldCluster, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return nil, invalidCluster(cluster, validateCluster(oldCluster, cluster, supported))
+}
+
+// supportedVersion returns the Kubernetes version of the node image of the ProviderConfig of the cluster. The
+// ProviderConfig is only looked up if the version of the cluster is to be validated.
+func (w *ClusterWebhook) supportedVersion(ctx context.Context, oldCluster, cluster *clustersv1alpha1.Cluster) (string, error) {
+	v := cluster.Spec.Kubernetes.Version
+	if v == "" || (oldCluster != nil && v == oldCluster.Spec.Kubernetes.Version) {
+		return "", nil
+	}
+	pc, err := getProviderConfig(ctx, w.Client, clusterProviderConfig(w.Config.ProviderName, cluster))
+	if err != nil {
+		return "", err
+	}
+	return kubernetesVersion(pc), nil
+}
+
+// ValidateDelete implements admission.Validator.
+func (w *ClusterWebhook) ValidateDelete(context.Context, *clustersv1alpha1.Cluster) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateCluster validates the cluster. If the old cluster is given, only fields that have been changed are validated,
+// and the annotations that identify the kind cluster and its LoadBalancer subnet must not be changed. The Kubernetes
+// version is validated against the supported version.
+func validateCluster(oldCluster, cluster *clustersv1alpha1.Cluster, supported string) field.ErrorList {
+	var errs field.ErrorList
+	isNew := oldCluster == nil
+	var oldVersion string
+	var oldAnnotations map[string]string
+	if !isNew {
+		oldVersion = oldCluster.Spec.Kubernetes.Version
+		oldAnnotations = oldCluster.Annotations
+	}
+	annotationsPath := field.NewPath("metadata", "annotations")
+
+	if v := cluster.Spec.Kubernetes.Version; v != "" && (isNew || v != oldVersion) {
+		if msg := validateKubernetesVersion(v, supported); msg != "" {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "kubernetes", "version"), v, msg))
+		}
+	}
+
+	if !isNew {
+		errs = append(errs, validateImmutableAnnotations(oldCluster, cluster)...)
+	} else if name, ok := cluster.Annotations[AnnotationName]; ok {
+		for _, msg := range validateKindName(name) {
+			errs = append(errs, field.Invalid(annotationsPath.Key(AnnotationName), name, msg))
+		}
+	} else if len(cluster.UID) >= 8 {
+		// The name of the kind cluster is composed of the name and the UID of the Cluster.
+		name := kindName(cluster)
+		for _, msg := range validateKindName(name) {
+			errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), cluster.Name, fmt.Sprintf("kind cluster name %q: %s", name, msg)))
+		}
+	}
+
+	if subnet, ok := cluster.Annotations[kind.AnnotationAssignedSubnet]; ok && (isNew || subnet != oldAnnotations[kind.AnnotationAssignedSubnet]) {
+		if msg := validateSubnet(subnet); msg != "" {
+			errs = append(errs, field.Invalid(annotationsPath.Key(kind.AnnotationAssignedSubnet), subnet, msg))
+		}
+	}
+
+	return errs
+}
+
+// validateImmutableAnnotations checks that the name of the kind cluster is not changed after the Cluster has been
+// created and that the LoadBalancer subnet is not changed once it has been assigned. Changing them would orphan the kind
+// cluster or break the MetalLB configuration.
+func validateImmutableAnnotations(oldCluster, cluster *clustersv1alpha1.Cluster) field.ErrorList {
+	var errs field.ErrorList
+	annotationsPath := field.NewPath("metadata", "annotations")
+
+	oldName, hadName := oldCluster.Annotations[AnnotationName]
+	name, hasName := cluster.Annotations[AnnotationName]
+	if hadName != hasName || oldName != name {
+		errs = append(errs, field.Forbidden(annotationsPath.Key(AnnotationName), "the name of the kind cluster cannot be changed"))
+	}
+
+	oldSubnet, hadSubnet := oldCluster.Annotations[kind.AnnotationAssignedSubnet]
+	subnet, hasSubnet := cluster.Annotations[kind.AnnotationAssignedSubnet]
+	if hadSubnet && (!hasSubnet || oldSubnet != subnet) {
+		errs = append(errs, field.Forbidden(annotationsPath.Key(kind.AnnotationAssignedSubnet), "the assigned subnet cannot be changed"))
+	}
+
+	return errs
+}
+
+// validateKubernetesVersion checks that the clusters are created with the given Kubernetes version, i.e. that it has
+// the same minor version as the supported version of the node image.
+func validateKubernetesVersion(v, supported string) string {
+	requested, err := version.ParseGeneric(v)
+	if err != nil {
+		return err.Error()
+	}
+	supportedVersion, err := version.ParseGeneric(supported)
+	if err != nil {
+		return fmt.Sprintf("unsupported version, the node image has no valid Kubernetes version %q", supported)
+	}
+	if requested.Major() != supportedVersion.Major() || requested.Minor() != supportedVersion.Minor() {
+		return fmt.Sprintf("unsupported version, the clusters are created with Kubernetes %s", supported)
+	}
+	return ""
+}
+
+// validateKindName checks that the name can be used for a kind cluster. Besides the restrictions of kind itself,
+// the hostnames of the node containers, e.g. <name>-control-plane, must be valid DNS names.
+func validateKindName(name string) []string {
+	hostname := name + nodeNameSuffix
+	msgs := validation.IsDNS1123Subdomain(hostname)
+	if len(msgs) == 0 {
+		for _, label := range strings.Split(hostname, ".") {
+			msgs = append(msgs, validation.IsDNS1123Label(label)...)
+		}
+	}
+	if len(msgs) > 0 {
+		return []string{fmt.Sprintf("invalid node hostname %q: %s", hostname, strings.Join(msgs, "; "))}
+	}
+	return nil
+}
+
+// validateSubnet checks that the subnet is an IPv4 CIDR in canonical form, like the subnets assigned by the controller.
+func validateSubnet(subnet string) string {
+	ip, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return "must be a CIDR, e.g. 172.18.200.0/24"
+	}
+	if ip.To4() == nil {
+		return "must be an IPv4 subnet"
+	}
+	if ipNet.String() != subnet {
+		return fmt.Sprintf("must be the network address %s", ipNet.String())
+	}
+	return ""
+}
+
+func invalidCluster(cluster *clustersv1alpha1.Cluster, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(clustersv1alpha1.GroupVersion.WithKind("Cluster").GroupKind(), cluster.Name, errs)
+}
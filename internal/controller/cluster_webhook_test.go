@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+func TestClusterWebhook_Default(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	testCases := []struct {
+		desc        string
+		isolation   v1alpha1.NetworkIsolation
+		profile     string
+		annotations map[string]string
+		expected    map[string]string
+	}{
+		{
+			desc:     "should not annotate the kind network",
+			profile:  profileKind,
+			expected: nil,
+		},
+		{
+			desc:      "should annotate the network of the tenant",
+			isolation: v1alpha1.NetworkIsolationTenant,
+			profile:   profileKind,
+			expected:  map[string]string{kind.AnnotationNetwork: "kind-tenant-tenant-a"},
+		},
+		{
+			desc:      "should leave the network per cluster to the controller",
+			isolation: v1alpha1.NetworkIsolationCluster,
+			profile:   profileKind,
+			expected:  nil,
+		},
+		{
+			desc:        "should annotate the network per cluster with an explicit name",
+			isolation:   v1alpha1.NetworkIsolationCluster,
+			profile:     profileKind,
+			annotations: map[string]string{AnnotationName: "one"},
+			expected:    map[string]string{AnnotationName: "one", kind.AnnotationNetwork: "kind-one"},
+		},
+		{
+			desc:        "should keep the network of clusters with a subnet",
+			isolation:   v1alpha1.NetworkIsolationTenant,
+			profile:     profileKind,
+			annotations: map[string]string{kind.AnnotationAssignedSubnet: "172.18.200.0/24"},
+			expected:    map[string]string{kind.AnnotationAssignedSubnet: "172.18.200.0/24"},
+		},
+		{
+			desc:      "should ignore clusters of other profiles",
+			isolation: v1alpha1.NetworkIsolationTenant,
+			profile:   "gardener",
+			expected:  nil,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			pc := &v1alpha1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: providerConfigName},
+				Spec:       v1alpha1.ProviderConfigSpec{Network: &v1alpha1.NetworkConfig{Isolation: tC.isolation}},
+			}
+			w := &ClusterWebhook{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(pc).Build()}
+			cluster := &clustersv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "one", Namespace: "tenant-a", Annotations: tC.annotations},
+				Spec:       clustersv1alpha1.ClusterSpec{Profile: tC.profile},
+			}
+
+			assert.NoError(t, w.Default(context.Background(), cluster))
+			assert.Equal(t, tC.expected, cluster.Annotations)
+		})
+	}
+}
+
+func Test_validateCluster(t *testing.T) {
+	valid := &clustersv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "one", Namespace: "tenant-a", UID: "6f2a311e-ac05-dd15-9140-c280f38b28f4"},
+		Spec: clustersv1alpha1.ClusterSpec{
+			Profile:    profileKind,
+			Kubernetes: clustersv1alpha1.K8sConfiguration{Version: kind.KubernetesVersion()},
+		},
+	}
+	withVersion := func(v string) *clustersv1alpha1.Cluster {
+		c := valid.DeepCopy()
+		c.Spec.Kubernetes.Version = v
+		return c
+	}
+	withName := func(name string) *clustersv1alpha1.Cluster {
+		c := valid.DeepCopy()
+		c.Name = name
+		return c
+	}
+	withAnnotation := func(key, value string) *clustersv1alpha1.Cluster {
+		c := valid.DeepCopy()
+		c.Annotations = map[string]string{key: value}
+		return c
+	}
+
+	testCases := []struct {
+		desc       string
+		oldCluster *clustersv1alpha1.Cluster
+		cluster    *clustersv1alpha1.Cluster
+		expected   []string
+	}{
+		{
+			desc:    "should accept a valid cluster",
+			cluster: valid,
+		},
+		{
+			desc:    "should accept a cluster without version",
+			cluster: withVersion(""),
+		},
+		{
+			desc:     "should reject an unsupported version",
+			cluster:  withVersion("v1.20.0"),
+			expected: []string{"spec.kubernetes.version"},
+		},
+		{
+			desc:     "should reject a malformed version",
+			cluster:  withVersion("latest"),
+			expected: []string{"spec.kubernetes.version"},
+		},
+		{
+			desc:     "should reject a name that is no valid hostname",
+			cluster:  withName("a-very-long-cluster-name-that-does-not-fit-into-a-single-dns-label"),
+			expected: []string{"metadata.name"},
+		},
+		{
+			desc:     "should reject an invalid name annotation",
+			cluster:  withAnnotation(AnnotationName, "My_Cluster"),
+			expected: []string{"metadata.annotations[kind.clusters.openmcp.cloud/name]"},
+		},
+		{
+			desc:    "should accept a valid name annotation",
+			cluster: withAnnotation(AnnotationName, "a-very-long-cluster-name-that-is-overridden"),
+		},
+		{
+			desc:     "should reject a subnet that is no CIDR",
+			cluster:  withAnnotation(kind.AnnotationAssignedSubnet, "172.18.200.0"),
+			expected: []string{"metadata.annotations[kind.clusters.openmcp.cloud/assigned-subnet]"},
+		},
+		{
+			desc:     "should reject a subnet that is not the network address",
+			cluster:  withAnnotation(kind.AnnotationAssignedSubnet, "172.18.200.1/24"),
+			expected: []string{"metadata.annotations[kind.clusters.openmcp.cloud/assigned-subnet]"},
+		},
+		{
+			desc:     "should reject an IPv6 subnet",
+			cluster:  withAnnotation(kind.AnnotationAssignedSubnet, "fc00:f853:ccd:e793::/64"),
+			expected: []string{"metadata.annotations[kind.clusters.openmcp.cloud/assigned-subnet]"},
+		},
+		{
+			desc:    "should accept a valid subnet",
+			cluster: withAnnotation(kind.AnnotationAssignedSubnet, "172.18.200.0/24"),
+		},
+		{
+			desc:       "should accept updates of clusters with unchanged invalid fields",
+			oldCluster: withVersion("v1.20.0"),
+			cluster:    withVersion("v1.20.0"),
+		},
+		{
+			desc:       "should reject changes of the name annotation",
+			oldCluster: withAnnotation(AnnotationName, "one"),
+			cluster:    withAnnotation(AnnotationName, "two"),
+			expected:   []string{"metadata.annotations[kind.clusters.openmcp.cloud/name]"},
+		},
+		{
+			desc:       "should reject adding the name annotation",
+			oldCluster: valid,
+			cluster:    withAnnotation(AnnotationName, "one"),
+			expected:   []string{"metadata.annotations[kind.clusters.openmcp.cloud/name]"},
+		},
+		{
+			desc:       "should reject removing the name annotation",
+			oldCluster: withAnnotation(AnnotationName, "one"),
+			cluster:    valid,
+			expected:   []string{"metadata.annotations[kind.clusters.openmcp.cloud/name]"},
+		},
+		{
+			desc:       "should accept assigning a subnet",
+			oldCluster: valid,
+			cluster:    withAnnotation(kind.AnnotationAssignedSubnet, "172.18.200.0/24"),
+		},
+		{
+			desc:       "should reject changes of the subnet",
+			oldCluster: withAnnotation(kind.AnnotationAssignedSubnet, "172.18.200.0/24"),
+			cluster:    withAnnotation(kind.AnnotationAssignedSubnet, "172.18.201.0/24"),
+			expected:   []string{"metadata.annotations[kind.clusters.openmcp.cloud/assigned-subnet]"},
+		},
+		{
+			desc:       "should reject removing the subnet",
+			oldCluster: withAnnotation(kind.AnnotationAssignedSubnet, "172.18.200.0/24"),
+			cluster:    valid,
+			expected:   []string{"metadata.annotations[kind.clusters.openmcp.cloud/assigned-subnet]"},
+		},
+		{
+			desc:       "should reject changes to an unsupported version",
+			oldCluster: valid,
+			cluster:    withVersion("v1.20.0"),
+			expected:   []string{"spec.kubernetes.version"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var fields []string
+			for _, err := range validateCluster(tC.oldCluster, tC.cluster, kind.KubernetesVersion()) {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tC.expected, fields)
+		})
+	}
+}
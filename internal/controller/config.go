@@ -1,55 +1,128 @@
 package controller
 
-var (
-	environment                          string
-	providerName                         string
-	accessRequestServiceAccountNamespace string
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
 )
 
-// SetEnvironment sets the environment
-func SetEnvironment(env string) {
-	if environment != "" {
-		panic("environment already set")
-	}
-	environment = env
+const (
+	flagProviderName                         = "provider-name"
+	flagEnvironment                          = "environment"
+	flagKindConfigFile                       = "kind-config-file"
+	flagAccessRequestServiceAccountNamespace = "access-request-service-account-namespace"
+	flagKindOnLocalHost                      = "kind-on-local-host"
+
+	envKindConfigFile                       = "KIND_CONFIG_FILE"
+	envAccessRequestServiceAccountNamespace = "ACCESS_REQUEST_SERVICE_ACCOUNT_NAMESPACE"
+	envKindOnLocalHost                      = "KIND_ON_LOCAL_HOST"
+)
+
+// Config is the configuration of the controllers.
+type Config struct {
+	// ProviderName is the name of the provider. It prefixes the generated ClusterProfiles.
+	ProviderName string `json:"providerName,omitempty"`
+	// Environment is the name of the environment. It is part of the names of the resources created for AccessRequests.
+	Environment string `json:"environment,omitempty"`
+	// KindConfigFile is the kind configuration file new clusters are created with.
+	KindConfigFile string `json:"kindConfigFile,omitempty"`
+	// AccessRequestServiceAccountNamespace is the namespace of the ServiceAccounts created for AccessRequests in the
+	// kind clusters.
+	AccessRequestServiceAccountNamespace string `json:"accessRequestServiceAccountNamespace,omitempty"`
+	// KindOnLocalHost is true if the provider runs on the Docker host instead of in a cluster. The kind clusters are
+	// then accessed via their published API server ports.
+	KindOnLocalHost bool `json:"kindOnLocalHost,omitempty"`
 }
 
-// Environment retrieves the environment
-func Environment() string {
-	if environment == "" {
-		panic("environment not set")
+// DefaultConfig returns the configuration with the default values.
+func DefaultConfig() Config {
+	return Config{
+		ProviderName:                         "kind",
+		AccessRequestServiceAccountNamespace: "accessrequests",
 	}
-	return environment
 }
 
-// SetProviderName sets the provider name
-func SetProviderName(name string) {
-	if providerName != "" {
-		panic("provider name already set")
-	}
-	providerName = name
+// BindFlags registers the flags of the configuration in the flag set. The values of the configuration are the defaults.
+func (c *Config) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.ProviderName, flagProviderName, c.ProviderName,
+		"The name of the provider. This is used to identify the provider in logs and metrics.")
+	fs.StringVar(&c.Environment, flagEnvironment, c.Environment, "The name of the environment to use for the provider.")
+	fs.StringVar(&c.KindConfigFile, flagKindConfigFile, c.KindConfigFile,
+		"The kind configuration file new clusters are created with. Overrides "+envKindConfigFile+".")
+	fs.StringVar(&c.AccessRequestServiceAccountNamespace, flagAccessRequestServiceAccountNamespace, c.AccessRequestServiceAccountNamespace,
+		"The namespace of the ServiceAccounts created for AccessRequests. Overrides "+envAccessRequestServiceAccountNamespace+".")
+	fs.BoolVar(&c.KindOnLocalHost, flagKindOnLocalHost, c.KindOnLocalHost,
+		"If set, the provider runs on the Docker host and accesses the kind clusters via localhost. Overrides "+envKindOnLocalHost+".")
 }
 
-// ProviderName retrieves the provider name
-func ProviderName() string {
-	if providerName == "" {
-		panic("provider name not set")
+// LoadConfig returns the validated configuration. Each value is taken from the first source that sets it: the flags
+// of the flag set that have been set explicitly, the environment, the config file and the defaults. The flags must have
+// been bound to flags with BindFlags. The config file is optional.
+func LoadConfig(fs *flag.FlagSet, flags Config, file string) (Config, error) {
+	cfg := DefaultConfig()
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config file %s: %w", file, err)
+		}
 	}
-	return providerName
-}
 
-// SetAccessRequestServiceAccountNamespace sets the service account namespace
-func SetAccessRequestServiceAccountNamespace(ns string) {
-	if accessRequestServiceAccountNamespace != "" {
-		panic("accessrequest namespace already set")
+	if value, ok := os.LookupEnv(envKindConfigFile); ok && value != "" {
+		cfg.KindConfigFile = value
 	}
-	accessRequestServiceAccountNamespace = ns
+	if value, ok := os.LookupEnv(envAccessRequestServiceAccountNamespace); ok && value != "" {
+		cfg.AccessRequestServiceAccountNamespace = value
+	}
+	if value, ok := os.LookupEnv(envKindOnLocalHost); ok && value != "" {
+		localHost, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid value %q of %s: %w", value, envKindOnLocalHost, err)
+		}
+		cfg.KindOnLocalHost = localHost
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case flagProviderName:
+			cfg.ProviderName = flags.ProviderName
+		case flagEnvironment:
+			cfg.Environment = flags.Environment
+		case flagKindConfigFile:
+			cfg.KindConfigFile = flags.KindConfigFile
+		case flagAccessRequestServiceAccountNamespace:
+			cfg.AccessRequestServiceAccountNamespace = flags.AccessRequestServiceAccountNamespace
+		case flagKindOnLocalHost:
+			cfg.KindOnLocalHost = flags.KindOnLocalHost
+		}
+	})
+
+	return cfg, cfg.Validate()
 }
 
-// AccessRequestServiceAccountNamespace retrieves the service account namespace
-func AccessRequestServiceAccountNamespace() string {
-	if accessRequestServiceAccountNamespace == "" {
-		panic("accessrequest namespace not set")
+// Validate checks that the configuration is complete and that the kind configuration file exists.
+func (c Config) Validate() error {
+	var errs []error
+	if msgs := validation.IsDNS1123Subdomain(c.ProviderName); len(msgs) > 0 {
+		errs = append(errs, fmt.Errorf("invalid provider name %q: %v", c.ProviderName, msgs))
+	}
+	if c.Environment == "" {
+		errs = append(errs, errors.New("environment must be set"))
+	}
+	if msgs := validation.IsDNS1123Label(c.AccessRequestServiceAccountNamespace); len(msgs) > 0 {
+		errs = append(errs, fmt.Errorf("invalid AccessRequest ServiceAccount namespace %q: %v", c.AccessRequestServiceAccountNamespace, msgs))
+	}
+	if c.KindConfigFile != "" {
+		if _, err := os.Stat(c.KindConfigFile); err != nil {
+			errs = append(errs, fmt.Errorf("invalid kind config file: %w", err))
+		}
 	}
-	return accessRequestServiceAccountNamespace
+	return errors.Join(errs...)
 }
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	kindConfig := filepath.Join(dir, "kind.yaml")
+	require.NoError(t, os.WriteFile(kindConfig, []byte("kind: Cluster\n"), 0o600))
+	file := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+providerName: kind-file
+environment: file
+accessRequestServiceAccountNamespace: file
+`), 0o600))
+	fileWithKindConfig := filepath.Join(dir, "config-kind.yaml")
+	require.NoError(t, os.WriteFile(fileWithKindConfig, []byte(`
+environment: file
+kindConfigFile: `+kindConfig+`
+`), 0o600))
+
+	testCases := []struct {
+		desc     string
+		file     string
+		env      map[string]string
+		args     []string
+		expected Config
+		err      bool
+	}{
+		{
+			desc: "should use the defaults",
+			args: []string{"--environment", "dev"},
+			expected: Config{
+				ProviderName:                         "kind",
+				Environment:                          "dev",
+				AccessRequestServiceAccountNamespace: "accessrequests",
+			},
+		},
+		{
+			desc: "should read the config file",
+			file: file,
+			expected: Config{
+				ProviderName:                         "kind-file",
+				Environment:                          "file",
+				AccessRequestServiceAccountNamespace: "file",
+			},
+		},
+		{
+			desc: "should prefer the environment over the config file",
+			file: file,
+			env: map[string]string{
+				envKindConfigFile:                       kindConfig,
+				envAccessRequestServiceAccountNamespace: "env",
+				envKindOnLocalHost:                      "true",
+			},
+			expected: Config{
+				ProviderName:                         "kind-file",
+				Environment:                          "file",
+				KindConfigFile:                       kindConfig,
+				AccessRequestServiceAccountNamespace: "env",
+				KindOnLocalHost:                      true,
+			},
+		},
+		{
+			desc: "should ignore an empty environment variable",
+			file: fileWithKindConfig,
+			env:  map[string]string{envKindConfigFile: ""},
+			expected: Config{
+				ProviderName:                         "kind",
+				Environment:                          "file",
+				KindConfigFile:                       kindConfig,
+				AccessRequestServiceAccountNamespace: "accessrequests",
+			},
+		},
+		{
+			desc: "should prefer flags over the environment",
+			file: file,
+			env: map[string]string{
+				envAccessRequestServiceAccountNamespace: "env",
+				envKindOnLocalHost:                      "true",
+			},
+			args: []string{"--provider-name", "kind-flag", "--access-request-service-account-namespace", "flag", "--kind-on-local-host=false"},
+			expected: Config{
+				ProviderName:                         "kind-flag",
+				Environment:                          "file",
+				AccessRequestServiceAccountNamespace: "flag",
+			},
+		},
+		{
+			desc: "should fail without environment",
+			err:  true,
+		},
+		{
+			desc: "should fail for an invalid boolean",
+			env:  map[string]string{envKindOnLocalHost: "yes"},
+			args: []string{"--environment", "dev"},
+			err:  true,
+		},
+		{
+			desc: "should fail for a missing kind config file",
+			args: []string{"--environment", "dev", "--kind-config-file", filepath.Join(dir, "missing.yaml")},
+			err:  true,
+		},
+		{
+			desc: "should fail for unknown fields in the config file",
+			file: kindConfig,
+			args: []string{"--environment", "dev"},
+			err:  true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			for _, key := range []string{envKindConfigFile, envAccessRequestServiceAccountNamespace, envKindOnLocalHost} {
+				t.Setenv(key, tC.env[key])
+			}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags := DefaultConfig()
+			flags.BindFlags(fs)
+			require.NoError(t, fs.Parse(tC.args))
+
+			actual, err := LoadConfig(fs, flags, tC.file)
+			if tC.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.expected, actual)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Environment = "dev"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ProviderName = ""
+	cfg.AccessRequestServiceAccountNamespace = "Access_Requests"
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, "invalid provider name")
+	assert.ErrorContains(t, err, "invalid AccessRequest ServiceAccount namespace")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	errutils "github.com/openmcp-project/controller-utils/pkg/errors"
+)
+
+const (
+	// reasonReconcileError is the event reason for errors that do not carry a reason.
+	reasonReconcileError = "ReconcileError"
+
+	actionReconcile = "Reconcile"
+)
+
+// errorEvents records reconciliation errors as Warning events.
+// An error is recorded only once as long as it repeats, so that requeues do not flood the object with events.
+type errorEvents struct {
+	lock sync.Mutex
+	last map[types.UID]string
+}
+
+// record records the error as Warning event on the object, unless it is the same as the last one recorded for it.
+// The reason of the event is taken from the error if it has been created with errutils.WithReason.
+func (e *errorEvents) record(recorder events.EventRecorder, obj client.Object, err error) {
+	reason := errorReason(err)
+	key := reason + ": " + err.Error()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.last == nil {
+		e.last = map[types.UID]string{}
+	}
+	if e.last[obj.GetUID()] == key {
+		return
+	}
+	e.last[obj.GetUID()] = key
+
+	recorder.Eventf(obj, nil, corev1.EventTypeWarning, reason, actionReconcile, "%s", err.Error())
+}
+
+// reset forgets the last error of the object, e.g. after a successful reconciliation or its deletion.
+func (e *errorEvents) reset(obj client.Object) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	delete(e.last, obj.GetUID())
+}
+
+// errorReason returns the reason of the error if it has one, otherwise reasonReconcileError.
+func errorReason(err error) string {
+	var rerr errutils.ReasonableError
+	if errors.As(err, &rerr) && rerr.Reason() != "" {
+		return rerr.Reason()
+	}
+	return reasonReconcileError
+}
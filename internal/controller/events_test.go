@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	errutils "github.com/openmcp-project/controller-utils/pkg/errors"
+)
+
+func Test_errorEvents(t *testing.T) {
+	recorder := events.NewFakeRecorder(10)
+	cluster := &clustersv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "one", UID: "6f2a311e"}}
+	other := &clustersv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "two", UID: "0b7c41d2"}}
+	e := errorEvents{}
+
+	e.record(recorder, cluster, errutils.WithReason(errors.New("connection refused"), reasonKindClusterInteractionError))
+	e.record(recorder, cluster, errutils.WithReason(errors.New("connection refused"), reasonKindClusterInteractionError))
+	e.record(recorder, other, errors.New("connection refused"))
+	e.record(recorder, cluster, errors.New("no subnets available"))
+	e.reset(cluster)
+	e.record(recorder, cluster, errors.New("no subnets available"))
+
+	assert.Equal(t, []string{
+		reasonKindClusterInteractionError,
+		reasonReconcileError,
+		reasonReconcileError,
+		reasonReconcileError,
+	}, eventReasons(recorder))
+}
+
+func Test_errorReason(t *testing.T) {
+	assert.Equal(t, reasonInternalError, errorReason(errutils.WithReason(errors.New("failed"), reasonInternalError)))
+	assert.Equal(t, reasonInternalError, errorReason(errors.Join(errutils.WithReason(errors.New("failed"), reasonInternalError))))
+	assert.Equal(t, reasonReconcileError, errorReason(errors.New("failed")))
+}
+
+// eventReasons returns the reasons of the events recorded so far.
+func eventReasons(recorder *events.FakeRecorder) []string {
+	var reasons []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			reasons = append(reasons, strings.Fields(e)[1])
+		default:
+			return reasons
+		}
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// failureThreshold is the number of consecutive failed reconciliations after which a Cluster is in phase Error.
+const failureThreshold = 3
+
+// failures counts the consecutive failed reconciliations of objects.
+type failures struct {
+	lock  sync.Mutex
+	count map[types.UID]int
+}
+
+// inc counts a failed reconciliation of the object and returns the number of consecutive failures.
+func (f *failures) inc(obj client.Object) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.count == nil {
+		f.count = map[types.UID]int{}
+	}
+	f.count[obj.GetUID()]++
+	return f.count[obj.GetUID()]
+}
+
+// reset forgets the failures of the object after a successful reconciliation.
+func (f *failures) reset(obj client.Object) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.count, obj.GetUID())
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+// defaultDockerHost is the name of the Docker host of a ProviderConfig that does not list any, i.e. the Docker daemon
+// of the provider.
+const defaultDockerHost = "default"
+
+// dockerHosts returns the Docker hosts of the ProviderConfig.
+func dockerHosts(pc *v1alpha1.ProviderConfig) []v1alpha1.DockerHost {
+	if len(pc.Spec.DockerHosts) == 0 {
+		return []v1alpha1.DockerHost{{Name: defaultDockerHost}}
+	}
+	return pc.Spec.DockerHosts
+}
+
+// dockerEndpoint returns the endpoint of the Docker host with the given name. Clusters without a Docker host have been
+// created before hosts could be configured and run on the Docker daemon of the provider.
+func dockerEndpoint(pc *v1alpha1.ProviderConfig, name string) (kind.DockerEndpoint, error) {
+	if name == "" {
+		return kind.DockerEndpoint{}, nil
+	}
+	for _, host := range dockerHosts(pc) {
+		if host.Name == name {
+			return kind.DockerEndpoint{Name: host.Name, Host: host.Host, TLSCertPath: host.TLSCertPath}, nil
+		}
+	}
+	return kind.DockerEndpoint{}, fmt.Errorf("docker host %s is not configured in ProviderConfig %s", name, pc.Name)
+}
+
+// withClusterDockerHost returns a context for the Docker host the cluster has been placed on.
+func withClusterDockerHost(ctx context.Context, c client.Reader, providerName string, cluster *clustersv1alpha1.Cluster) (context.Context, error) {
+	status, err := getProviderStatus(cluster)
+	if err != nil || status.DockerHost == "" {
+		return ctx, err
+	}
+	pc, err := getProviderConfig(ctx, c, clusterProviderConfig(providerName, cluster))
+	if err != nil {
+		return ctx, err
+	}
+	endpoint, err := dockerEndpoint(pc, status.DockerHost)
+	if err != nil {
+		return ctx, err
+	}
+	return kind.WithDockerEndpoint(ctx, endpoint), nil
+}
+
+// placeCluster returns the Docker host of the ProviderConfig a new cluster is created on, according to the placement
+// strategy. Clusters are the other clusters of the ProviderConfig.
+func placeCluster(pc *v1alpha1.ProviderConfig, cluster *clustersv1alpha1.Cluster, clusters []clustersv1alpha1.Cluster) (string, error) {
+	hosts := dockerHosts(pc)
+	placed := map[string]int{}
+	var last *clustersv1alpha1.Cluster
+	var lastHost string
+	for i := range clusters {
+		status, err := getProviderStatus(&clusters[i])
+		if err != nil {
+			return "", err
+		}
+		if status.DockerHost == "" {
+			continue
+		}
+		placed[status.DockerHost]++
+		if last == nil || last.CreationTimestamp.Before(&clusters[i].CreationTimestamp) {
+			last, lastHost = &clusters[i], status.DockerHost
+		}
+	}
+
+	leastLoaded := func(hosts []v1alpha1.DockerHost) string {
+		host := slices.MinFunc(hosts, func(a, b v1alpha1.DockerHost) int { return placed[a.Name] - placed[b.Name] })
+		return host.Name
+	}
+
+	strategy := v1alpha1.PlacementStrategyLeastLoaded
+	if pc.Spec.Placement != nil && pc.Spec.Placement.Strategy != "" {
+		strategy = pc.Spec.Placement.Strategy
+	}
+	switch strategy {
+	case v1alpha1.PlacementStrategyLabelMatch:
+		matching := slices.DeleteFunc(slices.Clone(hosts), func(host v1alpha1.DockerHost) bool {
+			for key, value := range host.Labels {
+				if actual, ok := cluster.Labels[key]; !ok || actual != value {
+					return true
+				}
+			}
+			return false
+		})
+		if len(matching) == 0 {
+			return "", fmt.Errorf("no Docker host of ProviderConfig %s matches the labels of the cluster", pc.Name)
+		}
+		return leastLoaded(matching), nil
+	case v1alpha1.PlacementStrategyRoundRobin:
+		i := slices.IndexFunc(hosts, func(host v1alpha1.DockerHost) bool { return host.Name == lastHost })
+		return hosts[(i+1)%len(hosts)].Name, nil
+	default:
+		return leastLoaded(hosts), nil
+	}
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+func placedCluster(t *testing.T, name, host string, created time.Time) clustersv1alpha1.Cluster {
+	t.Helper()
+	cluster := clustersv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+	}
+	require.NoError(t, setProviderStatus(&cluster, v1alpha1.ClusterStatus{DockerHost: host}))
+	return cluster
+}
+
+func Test_placeCluster(t *testing.T) {
+	created := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	hosts := []v1alpha1.DockerHost{
+		{Name: "one", Host: "unix:///var/run/docker.sock"},
+		{Name: "two", Host: "tcp://two.example.com:2376", Labels: map[string]string{"zone": "b"}},
+		{Name: "three", Host: "tcp://three.example.com:2376", Labels: map[string]string{"zone": "c", "gpu": "true"}},
+	}
+
+	testCases := []struct {
+		desc     string
+		hosts    []v1alpha1.DockerHost
+		strategy v1alpha1.PlacementStrategy
+		labels   map[string]string
+		clusters []clustersv1alpha1.Cluster
+		expected string
+		err      bool
+	}{
+		{
+			desc:     "should place clusters on the default host without configured hosts",
+			expected: defaultDockerHost,
+		},
+		{
+			desc:     "should place clusters on the first host if all are empty",
+			hosts:    hosts,
+			expected: "one",
+		},
+		{
+			desc:  "should place clusters on the least loaded host",
+			hosts: hosts,
+			clusters: []clustersv1alpha1.Cluster{
+				placedCluster(t, "a", "one", created),
+				placedCluster(t, "b", "two", created),
+				placedCluster(t, "c", "one", created),
+			},
+			expected: "three",
+		},
+		{
+			desc:  "should ignore clusters without host",
+			hosts: hosts,
+			clusters: []clustersv1alpha1.Cluster{
+				placedCluster(t, "a", "", created),
+				placedCluster(t, "b", "", created),
+			},
+			expected: "one",
+		},
+		{
+			desc:     "should place clusters on a host with matching labels",
+			hosts:    hosts[1:],
+			strategy: v1alpha1.PlacementStrategyLabelMatch,
+			labels:   map[string]string{"zone": "c", "gpu": "true", "team": "x"},
+			expected: "three",
+		},
+		{
+			desc:     "should treat hosts without labels as matching",
+			hosts:    hosts,
+			strategy: v1alpha1.PlacementStrategyLabelMatch,
+			labels:   map[string]string{"zone": "b"},
+			clusters: []clustersv1alpha1.Cluster{
+				placedCluster(t, "a", "one", created),
+			},
+			expected: "two",
+		},
+		{
+			desc:     "should fail if no host matches the labels",
+			hosts:    hosts[1:],
+			strategy: v1alpha1.PlacementStrategyLabelMatch,
+			labels:   map[string]string{"zone": "a"},
+			err:      true,
+		},
+		{
+			desc:     "should place clusters on the host after the last placed cluster",
+			hosts:    hosts,
+			strategy: v1alpha1.PlacementStrategyRoundRobin,
+			clusters: []clustersv1alpha1.Cluster{
+				placedCluster(t, "a", "two", created.Add(time.Minute)),
+				placedCluster(t, "b", "one", created),
+			},
+			expected: "three",
+		},
+		{
+			desc:     "should wrap around",
+			hosts:    hosts,
+			strategy: v1alpha1.PlacementStrategyRoundRobin,
+			clusters: []clustersv1alpha1.Cluster{
+				placedCluster(t, "a", "three", created),
+			},
+			expected: "one",
+		},
+		{
+			desc:     "should start with the first host",
+			hosts:    hosts,
+			strategy: v1alpha1.PlacementStrategyRoundRobin,
+			expected: "one",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			pc := &v1alpha1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "kind"},
+				Spec:       v1alpha1.ProviderConfigSpec{DockerHosts: tC.hosts},
+			}
+			if tC.strategy != "" {
+				pc.Spec.Placement = &v1alpha1.PlacementConfig{Strategy: tC.strategy}
+			}
+			cluster := &clustersv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "new", Labels: tC.labels}}
+
+			actual, err := placeCluster(pc, cluster, tC.clusters)
+			if tC.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.expected, actual)
+		})
+	}
+}
+
+func Test_dockerEndpoint(t *testing.T) {
+	pc := &v1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "kind"},
+		Spec: v1alpha1.ProviderConfigSpec{DockerHosts: []v1alpha1.DockerHost{
+			{Name: "remote", Host: "tcp://remote.example.com:2376", TLSCertPath: "/etc/docker/remote"},
+		}},
+	}
+
+	endpoint, err := dockerEndpoint(pc, "remote")
+	assert.NoError(t, err)
+	assert.Equal(t, kind.DockerEndpoint{Name: "remote", Host: "tcp://remote.example.com:2376", TLSCertPath: "/etc/docker/remote"}, endpoint)
+
+	// Clusters without a host run on the Docker daemon of the provider.
+	endpoint, err = dockerEndpoint(pc, "")
+	assert.NoError(t, err)
+	assert.Equal(t, kind.DockerEndpoint{}, endpoint)
+
+	_, err = dockerEndpoint(pc, "removed")
+	assert.Error(t, err)
+}
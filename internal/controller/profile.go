@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+)
+
+const (
+	// profileKind is the ClusterProfile that has been created by the init command before a ClusterProfile was
+	// generated for every ProviderConfig. Clusters with this profile use the ProviderConfig named kind.
+	profileKind = "kind"
+	// providerConfigName is the name of the default ProviderConfig, which is created by the init command.
+	providerConfigName = "kind"
+)
+
+// ProfileName returns the name of the ClusterProfile that is generated for the ProviderConfig, <provider>.<config>.
+func ProfileName(providerName, providerConfig string) string {
+	return providerName + "." + providerConfig
+}
+
+// profileProviderConfig returns the name of the ProviderConfig the clusters of the profile are created with, and false if
+// the profile does not belong to the provider.
+func profileProviderConfig(providerName, profile string) (string, bool) {
+	if profile == profileKind {
+		return providerConfigName, true
+	}
+	name, ok := strings.CutPrefix(profile, providerName+".")
+	return name, ok && name != ""
+}
+
+// clusterProviderConfig returns the name of the ProviderConfig of the cluster.
+func clusterProviderConfig(providerName string, cluster *clustersv1alpha1.Cluster) string {
+	name, _ := profileProviderConfig(providerName, cluster.Spec.Profile)
+	return name
+}
+
+func isClusterProviderResponsible(providerName string, cluster *clustersv1alpha1.Cluster) bool {
+	_, ok := profileProviderConfig(providerName, cluster.Spec.Profile)
+	return ok
+}
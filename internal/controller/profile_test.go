@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_profileProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		profile        string
+		providerConfig string
+		responsible    bool
+	}{
+		{
+			desc:           "should resolve the legacy profile to the default ProviderConfig",
+			profile:        "kind",
+			providerConfig: "kind",
+			responsible:    true,
+		},
+		{
+			desc:           "should resolve a generated profile",
+			profile:        "kind.kind-ha",
+			providerConfig: "kind-ha",
+			responsible:    true,
+		},
+		{
+			desc:           "should keep dots in the name of the ProviderConfig",
+			profile:        "kind.small.v2",
+			providerConfig: "small.v2",
+			responsible:    true,
+		},
+		{
+			desc:    "should not be responsible for profiles of other providers",
+			profile: "gardener.aws",
+		},
+		{
+			desc:    "should not be responsible for a profile without ProviderConfig",
+			profile: "kind.",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			providerConfig, responsible := profileProviderConfig("kind", tC.profile)
+			assert.Equal(t, tC.responsible, responsible)
+			if tC.responsible {
+				assert.Equal(t, tC.providerConfig, providerConfig)
+			}
+		})
+	}
+	assert.Equal(t, "kind.kind-ha", ProfileName("kind", "kind-ha"))
+}
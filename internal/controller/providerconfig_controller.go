@@ -0,0 +1,292 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	commonapi "github.com/openmcp-project/openmcp-operator/api/common"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/metrics"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/tracing"
+)
+
+const (
+	// ConditionDockerAvailable is the condition type of the ProviderConfig that reports whether the Docker daemons are reachable.
+	ConditionDockerAvailable = "DockerAvailable"
+	// ConditionLoadBalancerSubnetsAvailable is the condition type of the ProviderConfig that reports whether all
+	// networks have free LoadBalancer subnets for new clusters.
+	ConditionLoadBalancerSubnetsAvailable = "LoadBalancerSubnetsAvailable"
+
+	defaultStatusInterval = time.Minute
+)
+
+// ProviderConfigReconciler generates a ClusterProfile for every ProviderConfig and periodically reports the health of
+// the Docker hosts and the networks of the kind clusters in the status of the ProviderConfigs.
+type ProviderConfigReconciler struct {
+	Config Config
+	client.Client
+	Scheme   *runtime.Scheme
+	Host     kind.Host
+	Recorder events.EventRecorder
+
+	// Interval is the interval in which the status is refreshed. Defaults to one minute.
+	Interval time.Duration
+}
+
+// Reconcile ensures the ClusterProfile of the ProviderConfig, checks the Docker hosts and updates the status of the
+// ProviderConfig.
+func (r *ProviderConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ProviderConfigReconciler.Reconcile", tracing.AttrName.String(req.Name))
+	defer func() { tracing.End(span, err) }()
+
+	log := logf.FromContext(ctx)
+
+	pc := &v1alpha1.ProviderConfig{}
+	if err := r.Get(ctx, req.NamespacedName, pc); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !pc.DeletionTimestamp.IsZero() {
+		// The ClusterProfile is deleted by the garbage collector.
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.ensureClusterProfile(ctx, pc); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status, err := r.check(ctx, pc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	wasAvailable := meta.IsStatusConditionTrue(pc.Status.Conditions, ConditionDockerAvailable)
+	if condition := meta.FindStatusCondition(status.Conditions, ConditionDockerAvailable); wasAvailable && condition.Status != metav1.ConditionTrue {
+		r.Recorder.Eventf(pc, nil, corev1.EventTypeWarning, "DockerUnavailable", actionReconcile, "Docker daemon is not reachable: %s", condition.Message)
+	} else if !wasAvailable && condition.Status == metav1.ConditionTrue {
+		r.Recorder.Eventf(pc, nil, corev1.EventTypeNormal, "DockerAvailable", actionReconcile, "Docker daemon is reachable: %s", condition.Message)
+	}
+
+	if !equality.Semantic.DeepEqual(pc.Status, status) {
+		pc.Status = status
+		if err := r.Status().Update(ctx, pc); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+	if status.LastError != "" {
+		log.Info("Docker host check failed", "error", status.LastError)
+	}
+
+	return ctrl.Result{RequeueAfter: r.interval()}, nil
+}
+
+// check inspects the Docker hosts and returns the new status of the ProviderConfig. Failures of the Docker hosts are
+// reported in the status; only failures to read from the platform cluster are returned.
+func (r *ProviderConfigReconciler) check(ctx context.Context, pc *v1alpha1.ProviderConfig) (v1alpha1.ProviderConfigStatus, error) {
+	status := v1alpha1.ProviderConfigStatus{
+		ObservedGeneration: pc.Generation,
+		Conditions:         slices.Clone(pc.Status.Conditions),
+	}
+
+	clusters := &clustersv1alpha1.ClusterList{}
+	if err := r.List(ctx, clusters); err != nil {
+		return status, err
+	}
+	hosts := dockerHosts(pc)
+	networks := map[string][]string{}
+	for _, host := range hosts {
+		networks[host.Name] = []string{kind.DefaultNetworkName}
+	}
+	clustersOnHost := map[string]int32{}
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if name, ok := profileProviderConfig(r.Config.ProviderName, cluster.Spec.Profile); !ok || name != pc.Name {
+			continue
+		}
+		status.Clusters++
+		host, err := kind.DockerHostFromCluster(cluster)
+		if err != nil {
+			return status, err
+		}
+		if _, ok := networks[host]; !ok {
+			continue
+		}
+		clustersOnHost[host]++
+		if network := kind.NetworkFromCluster(cluster); !slices.Contains(networks[host], network) {
+			networks[host] = append(networks[host], network)
+		}
+	}
+
+	var errs, unreachable []error
+	var reachable, exhausted []string
+	for _, host := range hosts {
+		slices.Sort(networks[host.Name])
+		hostStatus, err := r.checkHost(ctx, host, networks[host.Name], clusters)
+		hostStatus.Clusters = clustersOnHost[host.Name]
+		status.DockerHosts = append(status.DockerHosts, hostStatus)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if !hostStatus.Reachable {
+			unreachable = append(unreachable, err)
+			continue
+		}
+		reachable = append(reachable, fmt.Sprintf("%s: Docker %s", host.Name, hostStatus.Version))
+		for _, network := range hostStatus.Networks {
+			if network.FreeLoadBalancerSubnets == 0 {
+				exhausted = append(exhausted, host.Name+"/"+network.Name)
+			}
+		}
+	}
+
+	if len(unreachable) > 0 {
+		setProviderConfigCondition(&status, pc, ConditionDockerAvailable, metav1.ConditionFalse, "DockerUnavailable", errors.Join(unreachable...).Error())
+	} else {
+		setProviderConfigCondition(&status, pc, ConditionDockerAvailable, metav1.ConditionTrue, "DockerAvailable", strings.Join(reachable, ", "))
+	}
+	switch {
+	case len(reachable) == 0:
+		meta.RemoveStatusCondition(&status.Conditions, ConditionLoadBalancerSubnetsAvailable)
+	case len(exhausted) > 0:
+		setProviderConfigCondition(&status, pc, ConditionLoadBalancerSubnetsAvailable, metav1.ConditionFalse, "SubnetsExhausted",
+			fmt.Sprintf("No free LoadBalancer subnets in networks %v", exhausted))
+	default:
+		setProviderConfigCondition(&status, pc, ConditionLoadBalancerSubnetsAvailable, metav1.ConditionTrue, "SubnetsAvailable", "")
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		status.LastError = err.Error()
+	}
+	return status, nil
+}
+
+// checkHost inspects the Docker daemon of the host and the given networks on it. The networks and images are only
+// checked if the daemon is reachable. The utilization of the LoadBalancer subnets is reported as metrics, too, so that
+// it decreases when clusters are deleted.
+func (r *ProviderConfigReconciler) checkHost(ctx context.Context, host v1alpha1.DockerHost, networks []string, clusters *clustersv1alpha1.ClusterList) (v1alpha1.DockerHostStatus, error) {
+	status := v1alpha1.DockerHostStatus{Name: host.Name}
+	ctx = kind.WithDockerEndpoint(ctx, kind.DockerEndpoint{Name: host.Name, Host: host.Host, TLSCertPath: host.TLSCertPath})
+
+	version, err := r.Host.DockerVersion(ctx)
+	if err != nil {
+		return status, fmt.Errorf("docker host %s: %w", host.Name, err)
+	}
+	status.Reachable = true
+	status.Version = version
+
+	// The metrics of networks without clusters are removed, so that only the checked networks are reported.
+	metrics.ResetLoadBalancerSubnets(host.Name)
+	var errs []error
+	for _, network := range networks {
+		subnet, err := r.Host.NetworkSubnet(ctx, network)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("docker host %s: failed to inspect network %s: %w", host.Name, network, err))
+			continue
+		}
+		free, err := kind.FreeLBSubnets(subnet, host.Name, network, clusters)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("docker host %s: failed to determine free LoadBalancer subnets in network %s: %w", host.Name, network, err))
+			continue
+		}
+		kind.ObserveLBSubnets(ctx, network, len(free))
+		status.Networks = append(status.Networks, v1alpha1.NetworkStatus{
+			Name:                    network,
+			Subnet:                  subnet.String(),
+			FreeLoadBalancerSubnets: int32(len(free)),
+		})
+	}
+
+	images, err := r.Host.NodeImages(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("docker host %s: %w", host.Name, err))
+	}
+	status.NodeImages = images
+
+	return status, errors.Join(errs...)
+}
+
+// ensureClusterProfile creates or updates the ClusterProfile of the ProviderConfig, which is owned by the ProviderConfig
+// and deleted with it.
+func (r *ProviderConfigReconciler) ensureClusterProfile(ctx context.Context, pc *v1alpha1.ProviderConfig) error {
+	cp := &clustersv1alpha1.ClusterProfile{ObjectMeta: metav1.ObjectMeta{Name: ProfileName(r.Config.ProviderName, pc.Name)}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, cp, func() error {
+		cp.Spec = clustersv1alpha1.ClusterProfileSpec{
+			ProviderRef:       commonapi.LocalObjectReference{Name: r.Config.ProviderName},
+			ProviderConfigRef: commonapi.LocalObjectReference{Name: pc.Name},
+			SupportedVersions: supportedVersions(pc),
+		}
+		return controllerutil.SetControllerReference(pc, cp, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update ClusterProfile %s: %w", cp.Name, err)
+	}
+	if result != controllerutil.OperationResultNone {
+		logf.FromContext(ctx).Info("ClusterProfile "+string(result), "name", cp.Name)
+	}
+	return nil
+}
+
+// supportedVersions returns the Kubernetes versions of the clusters of the ProviderConfig, i.e. the version of its node
+// image without the v prefix.
+func supportedVersions(pc *v1alpha1.ProviderConfig) []clustersv1alpha1.SupportedK8sVersion {
+	return []clustersv1alpha1.SupportedK8sVersion{{Version: strings.TrimPrefix(kubernetesVersion(pc), "v")}}
+}
+
+func setProviderConfigCondition(status *v1alpha1.ProviderConfigStatus, pc *v1alpha1.ProviderConfig, conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: pc.Generation,
+	})
+}
+
+func (r *ProviderConfigReconciler) interval() time.Duration {
+	if r.Interval > 0 {
+		return r.Interval
+	}
+	return defaultStatusInterval
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ProviderConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ProviderConfig{}).
+		Owns(&clustersv1alpha1.ClusterProfile{}).
+		Named("providerconfig").
+		Complete(r)
+}
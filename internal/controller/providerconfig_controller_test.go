@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/metrics"
+)
+
+type fakeHost struct {
+	version string
+	err     error
+	images  []string
+	subnets map[string]string
+}
+
+func (h *fakeHost) DockerVersion(context.Context) (string, error) {
+	return h.version, h.err
+}
+
+func (h *fakeHost) NodeImages(context.Context) ([]string, error) {
+	return h.images, h.err
+}
+
+func (h *fakeHost) NetworkSubnet(_ context.Context, network string) (net.IPNet, error) {
+	subnet, ok := h.subnets[network]
+	if !ok {
+		return net.IPNet{}, errors.New("network not found")
+	}
+	_, ipNet, err := net.ParseCIDR(subnet)
+	return *ipNet, err
+}
+
+func TestProviderConfigReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	pc := &v1alpha1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: providerConfigName, Generation: 2}}
+	objects := []client.Object{
+		pc,
+		&clustersv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "default",
+				Namespace:   "default",
+				Annotations: map[string]string{kind.AnnotationAssignedSubnet: "172.18.200.0/24"},
+			},
+			Spec: clustersv1alpha1.ClusterSpec{Profile: profileKind},
+		},
+		&clustersv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "isolated",
+				Namespace:   "default",
+				Annotations: map[string]string{kind.AnnotationNetwork: "kind-tenant-default"},
+			},
+			Spec: clustersv1alpha1.ClusterSpec{Profile: profileKind},
+		},
+		&clustersv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "small", Namespace: "default"},
+			Spec:       clustersv1alpha1.ClusterSpec{Profile: "kind.small"},
+		},
+		&clustersv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+			Spec:       clustersv1alpha1.ClusterSpec{Profile: "gardener"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(pc).Build()
+	host := &fakeHost{
+		version: "28.1.1",
+		images:  []string{"kindest/node:v1.36.1"},
+		subnets: map[string]string{
+			kind.DefaultNetworkName: "172.18.0.0/16",
+			"kind-tenant-default":   "172.19.0.0/16",
+		},
+	}
+	recorder := events.NewFakeRecorder(10)
+	r := &ProviderConfigReconciler{
+		Config:   Config{ProviderName: "kind"},
+		Client:   c,
+		Scheme:   scheme,
+		Host:     host,
+		Recorder: recorder,
+		Interval: 30 * time.Second,
+	}
+
+	reconcile := func() *v1alpha1.ProviderConfig {
+		t.Helper()
+		res, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pc)})
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, res.RequeueAfter)
+		actual := &v1alpha1.ProviderConfig{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(pc), actual))
+		return actual
+	}
+
+	actual := reconcile()
+	cp := &clustersv1alpha1.ClusterProfile{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: "kind.kind"}, cp))
+	assert.Equal(t, "kind", cp.Spec.ProviderRef.Name)
+	assert.Equal(t, providerConfigName, cp.Spec.ProviderConfigRef.Name)
+	assert.Equal(t, []clustersv1alpha1.SupportedK8sVersion{{Version: strings.TrimPrefix(kind.KubernetesVersion(), "v")}}, cp.Spec.SupportedVersions)
+	assert.True(t, metav1.IsControlledBy(cp, actual))
+
+	assert.Equal(t, int64(2), actual.Status.ObservedGeneration)
+	assert.Equal(t, int32(2), actual.Status.Clusters)
+	assert.Equal(t, []v1alpha1.DockerHostStatus{{
+		Name:      defaultDockerHost,
+		Reachable: true,
+		Version:   "28.1.1",
+		Clusters:  2,
+		Networks: []v1alpha1.NetworkStatus{
+			{Name: kind.DefaultNetworkName, Subnet: "172.18.0.0/16", FreeLoadBalancerSubnets: 55},
+			{Name: "kind-tenant-default", Subnet: "172.19.0.0/16", FreeLoadBalancerSubnets: 56},
+		},
+		NodeImages: []string{"kindest/node:v1.36.1"},
+	}}, actual.Status.DockerHosts)
+	assert.Empty(t, actual.Status.LastError)
+	assert.True(t, meta.IsStatusConditionTrue(actual.Status.Conditions, ConditionDockerAvailable))
+	assert.True(t, meta.IsStatusConditionTrue(actual.Status.Conditions, ConditionLoadBalancerSubnetsAvailable))
+	assert.Equal(t, []string{"DockerAvailable"}, eventReasons(recorder))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.LoadBalancerSubnetsUsed.WithLabelValues(defaultDockerHost, kind.DefaultNetworkName)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.LoadBalancerSubnetsUsed.WithLabelValues(defaultDockerHost, "kind-tenant-default")))
+
+	// The Docker socket becomes unavailable.
+	host.err = errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock")
+	actual = reconcile()
+	require.Len(t, actual.Status.DockerHosts, 1)
+	assert.False(t, actual.Status.DockerHosts[0].Reachable)
+	assert.Empty(t, actual.Status.DockerHosts[0].Networks)
+	assert.Equal(t, int32(2), actual.Status.Clusters)
+	assert.Equal(t, "docker host default: "+host.err.Error(), actual.Status.LastError)
+	assert.True(t, meta.IsStatusConditionFalse(actual.Status.Conditions, ConditionDockerAvailable))
+	assert.Nil(t, meta.FindStatusCondition(actual.Status.Conditions, ConditionLoadBalancerSubnetsAvailable))
+	assert.Equal(t, []string{"DockerUnavailable"}, eventReasons(recorder))
+
+	// Events are only recorded on transitions.
+	reconcile()
+	assert.Empty(t, eventReasons(recorder))
+
+	host.err = nil
+	actual = reconcile()
+	assert.True(t, actual.Status.DockerHosts[0].Reachable)
+	assert.Empty(t, actual.Status.LastError)
+	assert.True(t, meta.IsStatusConditionTrue(actual.Status.Conditions, ConditionDockerAvailable))
+	assert.Equal(t, []string{"DockerAvailable"}, eventReasons(recorder))
+}
+
+func TestProviderConfigReconciler_supportedVersions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	pcs := []*v1alpha1.ProviderConfig{
+		{ObjectMeta: metav1.ObjectMeta{Name: "old"}, Spec: v1alpha1.ProviderConfigSpec{NodeImage: "kindest/node:v1.32.5"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "new"}, Spec: v1alpha1.ProviderConfigSpec{NodeImage: "kindest/node:v1.33.1@sha256:050072256b9a903bd914c0b2866828150cb229cea0efe5892e2b644d5dd3b34f"}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pcs[0], pcs[1]).WithStatusSubresource(pcs[0], pcs[1]).Build()
+	r := &ProviderConfigReconciler{
+		Config:   Config{ProviderName: "kind"},
+		Client:   c,
+		Scheme:   scheme,
+		Host:     &fakeHost{version: "28.1.1", subnets: map[string]string{kind.DefaultNetworkName: "172.18.0.0/16"}},
+		Recorder: events.NewFakeRecorder(10),
+		Interval: 30 * time.Second,
+	}
+
+	expected := map[string]string{"old": "1.32.5", "new": "1.33.1"}
+	for _, pc := range pcs {
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pc)})
+		require.NoError(t, err)
+
+		cp := &clustersv1alpha1.ClusterProfile{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: ProfileName("kind", pc.Name)}, cp))
+		assert.Equal(t, []clustersv1alpha1.SupportedK8sVersion{{Version: expected[pc.Name]}}, cp.Spec.SupportedVersions)
+	}
+}
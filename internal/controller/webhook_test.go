@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	commonapi "github.com/openmcp-project/openmcp-operator/api/common"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+// startWebhooks starts an API server with the CRDs and webhook configurations of the provider and serves the webhooks.
+// The test is skipped if the envtest binaries are not available, see https://book.kubebuilder.io/reference/envtest.
+func startWebhooks(t *testing.T) client.Client {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	// The Cluster and AccessRequest CRDs are part of the openmcp-operator API module.
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "github.com/openmcp-project/openmcp-operator/api").Output()
+	require.NoError(t, err)
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths: []string{
+			filepath.Join(strings.TrimSpace(string(out)), "crds", "manifests"),
+			filepath.Join("..", "..", "api", "crds", "manifests"),
+		},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "config", "webhook")},
+		},
+	}
+	cfg, err := env.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, env.Stop()) })
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	opts := env.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    opts.LocalServingHost,
+			Port:    opts.LocalServingPort,
+			CertDir: opts.LocalServingCertDir,
+		}),
+	})
+	require.NoError(t, err)
+	require.NoError(t, (&ClusterWebhook{Config: Config{ProviderName: "kind"}, Client: mgr.GetClient()}).SetupWebhookWithManager(mgr))
+	require.NoError(t, (&AccessRequestWebhook{Config: Config{ProviderName: "kind"}}).SetupWebhookWithManager(mgr))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		assert.NoError(t, mgr.Start(ctx))
+	}()
+
+	addr := fmt.Sprintf("%s:%d", opts.LocalServingHost, opts.LocalServingPort)
+	require.Eventually(t, func() bool {
+		conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+		if err != nil {
+			return false
+		}
+		return conn.Close() == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	require.NoError(t, err)
+	return c
+}
+
+func TestWebhooks(t *testing.T) {
+	c := startWebhooks(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.Create(ctx, &v1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: providerConfigName},
+		Spec:       v1alpha1.ProviderConfigSpec{Network: &v1alpha1.NetworkConfig{Isolation: v1alpha1.NetworkIsolationTenant}},
+	}))
+
+	newCluster := func(name string, mutate func(*clustersv1alpha1.Cluster)) *clustersv1alpha1.Cluster {
+		cluster := &clustersv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: clustersv1alpha1.ClusterSpec{
+				Profile: profileKind,
+				Tenancy: clustersv1alpha1.TENANCY_EXCLUSIVE,
+			},
+		}
+		if mutate != nil {
+			mutate(cluster)
+		}
+		return cluster
+	}
+
+	t.Run("should default the network of a Cluster", func(t *testing.T) {
+		cluster := newCluster("defaulted", nil)
+		require.NoError(t, c.Create(ctx, cluster))
+		assert.Equal(t, "kind-tenant-default", cluster.Annotations[kind.AnnotationNetwork])
+
+		// Unchanged fields are not validated again.
+		metav1.SetMetaDataLabel(&cluster.ObjectMeta, "test", "updated")
+		assert.NoError(t, c.Update(ctx, cluster))
+	})
+
+	t.Run("should reject changes of the assigned subnet", func(t *testing.T) {
+		cluster := newCluster("immutable", nil)
+		metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationAssignedSubnet, "172.18.200.0/24")
+		require.NoError(t, c.Create(ctx, cluster))
+
+		metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationAssignedSubnet, "172.18.201.0/24")
+		err := c.Update(ctx, cluster)
+		assert.True(t, apierrors.IsInvalid(err), "expected invalid error, got %v", err)
+	})
+
+	t.Run("should reject an unsupported version", func(t *testing.T) {
+		err := c.Create(ctx, newCluster("old", func(c *clustersv1alpha1.Cluster) { c.Spec.Kubernetes.Version = "v1.20.0" }))
+		assert.True(t, apierrors.IsInvalid(err), "expected invalid error, got %v", err)
+	})
+
+	t.Run("should reject a malformed subnet annotation", func(t *testing.T) {
+		err := c.Create(ctx, newCluster("subnet", func(c *clustersv1alpha1.Cluster) {
+			c.Annotations = map[string]string{kind.AnnotationAssignedSubnet: "172.18.200.1/24"}
+		}))
+		assert.True(t, apierrors.IsInvalid(err), "expected invalid error, got %v", err)
+	})
+
+	t.Run("should reject a malformed name annotation", func(t *testing.T) {
+		err := c.Create(ctx, newCluster("name", func(c *clustersv1alpha1.Cluster) {
+			c.Annotations = map[string]string{AnnotationName: "My_Cluster"}
+		}))
+		assert.True(t, apierrors.IsInvalid(err), "expected invalid error, got %v", err)
+	})
+
+	t.Run("should ignore Clusters of other profiles", func(t *testing.T) {
+		cluster := newCluster("other", func(c *clustersv1alpha1.Cluster) {
+			c.Spec.Profile = "gardener"
+			c.Spec.Kubernetes.Version = "v1.20.0"
+		})
+		assert.NoError(t, c.Create(ctx, cluster))
+		assert.NotContains(t, cluster.Annotations, kind.AnnotationNetwork)
+	})
+
+	t.Run("should reject a Role reference without namespace", func(t *testing.T) {
+		err := c.Create(ctx, &clustersv1alpha1.AccessRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "role",
+				Namespace: "default",
+				Labels:    map[string]string{clustersv1alpha1.ProviderLabel: "kind", clustersv1alpha1.ProfileLabel: profileKind},
+			},
+			Spec: clustersv1alpha1.AccessRequestSpec{
+				ClusterRef: &commonapi.ObjectReference{Name: "defaulted", Namespace: "default"},
+				Token: &clustersv1alpha1.TokenConfig{
+					RoleRefs: []commonapi.RoleRef{{Kind: "Role", Name: "view"}},
+				},
+			},
+		})
+		assert.True(t, apierrors.IsInvalid(err), "expected invalid error, got %v", err)
+	})
+}
@@ -0,0 +1,187 @@
+package addon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+var (
+	// ErrPending can be returned by Configure if the configuration has been applied, but has not taken effect yet.
+	ErrPending = errors.New("addon configuration pending")
+
+	errUnknownAddon = errors.New("unknown addon")
+)
+
+// Addon is a component that is installed into every kind cluster.
+type Addon interface {
+	// Name returns the name of the addon. It is used to enable the addon in the ProviderConfig
+	// and for the condition type "<Name>Ready" on the Cluster.
+	Name() string
+
+	// Install installs or upgrades the addon in the kind cluster.
+	Install(ctx context.Context, ac Context) error
+
+	// Ready returns true if the installed components of the addon are ready.
+	Ready(ctx context.Context, ac Context) (bool, error)
+
+	// Configure configures the addon after its components are ready.
+	Configure(ctx context.Context, ac Context) error
+
+	// Uninstall removes the addon from the kind cluster.
+	Uninstall(ctx context.Context, ac Context) error
+}
+
+// Finalizer is implemented by addons that manage resources outside of the kind cluster,
+// which have to be removed when the kind cluster is deleted.
+type Finalizer interface {
+	// Finalize removes the resources of the addon that belong to the given kind cluster.
+	Finalize(ctx context.Context, kindName string) error
+}
+
+// ClusterConfigurer is implemented by addons with requirements that can only be met when the kind cluster is created.
+type ClusterConfigurer interface {
+	// ConfigureCluster adds the requirements of the addon to the configuration the kind cluster is created with.
+	ConfigureCluster(cfg *kind.ClusterConfig)
+}
+
+// Context describes the kind cluster an addon is reconciled in.
+type Context struct {
+	// Cluster is the Cluster resource of the kind cluster. Addons may set additional conditions on it.
+	Cluster *clustersv1alpha1.Cluster
+	// KindName is the name of the kind cluster.
+	KindName string
+	// Client is a client for the kind cluster.
+	Client client.Client
+	// Config is the ProviderConfig of the provider.
+	Config *v1alpha1.ProviderConfig
+	// ProviderStatus is the provider-specific status of the Cluster. Addons may report their state in it.
+	ProviderStatus *v1alpha1.ClusterStatus
+	// Recorder records events on the Cluster resource.
+	Recorder events.EventRecorder
+}
+
+// ConditionType returns the type of the condition that reports the readiness of the given addon.
+func ConditionType(a Addon) string {
+	return a.Name() + "Ready"
+}
+
+// Enabled returns the addons whose names are contained in the given list, in the order of the available addons.
+// Names are matched case-insensitively.
+func Enabled(available []Addon, names []string) ([]Addon, error) {
+	for _, name := range names {
+		if !slices.ContainsFunc(available, func(a Addon) bool { return strings.EqualFold(a.Name(), name) }) {
+			return nil, fmt.Errorf("%w %q", errUnknownAddon, name)
+		}
+	}
+
+	enabled := []Addon{}
+	for _, a := range available {
+		if slices.ContainsFunc(names, func(name string) bool { return strings.EqualFold(a.Name(), name) }) {
+			enabled = append(enabled, a)
+		}
+	}
+	return enabled, nil
+}
+
+// Reconcile installs and configures the enabled addons and uninstalls the available addons that are not enabled anymore.
+// The readiness of each addon is reported as condition on the Cluster. The enabled addons are reconciled in order,
+// an addon is only reconciled once all previous addons are ready. It returns true if all enabled addons are ready.
+func Reconcile(ctx context.Context, ac Context, available, enabled []Addon) (bool, error) {
+	for _, a := range available {
+		if slices.Contains(enabled, a) {
+			continue
+		}
+		if meta.FindStatusCondition(ac.Cluster.Status.Conditions, ConditionType(a)) == nil {
+			continue
+		}
+		if err := a.Uninstall(ctx, ac); err != nil {
+			return false, fmt.Errorf("failed to uninstall addon %s: %w", a.Name(), err)
+		}
+		meta.RemoveStatusCondition(&ac.Cluster.Status.Conditions, ConditionType(a))
+	}
+
+	for _, a := range enabled {
+		ready, err := reconcileAddon(ctx, ac, a)
+		if err != nil || !ready {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// ClusterConfig returns the configuration the kind cluster is created with, as required by the given addons.
+func ClusterConfig(enabled []Addon) kind.ClusterConfig {
+	cfg := kind.ClusterConfig{}
+	for _, a := range enabled {
+		if c, ok := a.(ClusterConfigurer); ok {
+			c.ConfigureCluster(&cfg)
+		}
+	}
+	return cfg
+}
+
+// Finalize calls Finalize on all addons that implement Finalizer.
+func Finalize(ctx context.Context, available []Addon, kindName string) error {
+	errs := []error{}
+	for _, a := range available {
+		if f, ok := a.(Finalizer); ok {
+			errs = append(errs, f.Finalize(ctx, kindName))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func reconcileAddon(ctx context.Context, ac Context, a Addon) (bool, error) {
+	if err := a.Install(ctx, ac); err != nil {
+		setCondition(ac, a, metav1.ConditionFalse, "InstallFailed", err.Error())
+		return false, fmt.Errorf("failed to install addon %s: %w", a.Name(), err)
+	}
+
+	ready, err := a.Ready(ctx, ac)
+	if err != nil {
+		setCondition(ac, a, metav1.ConditionFalse, "ReadinessCheckFailed", err.Error())
+		return false, fmt.Errorf("failed to check readiness of addon %s: %w", a.Name(), err)
+	}
+	if !ready {
+		setCondition(ac, a, metav1.ConditionFalse, "NotReady", "Waiting for the addon components to become ready")
+		return false, nil
+	}
+
+	if err := a.Configure(ctx, ac); err != nil {
+		if errors.Is(err, ErrPending) {
+			setCondition(ac, a, metav1.ConditionFalse, "ConfigurationPending", err.Error())
+			return false, nil
+		}
+		setCondition(ac, a, metav1.ConditionFalse, "ConfigurationFailed", err.Error())
+		return false, fmt.Errorf("failed to configure addon %s: %w", a.Name(), err)
+	}
+
+	if !meta.IsStatusConditionTrue(ac.Cluster.Status.Conditions, ConditionType(a)) {
+		ac.Recorder.Eventf(ac.Cluster, nil, corev1.EventTypeNormal, a.Name()+"Ready", "ReconcileAddon", "Addon %s is ready", a.Name())
+	}
+	setCondition(ac, a, metav1.ConditionTrue, "AddonReady", "")
+	return true, nil
+}
+
+func setCondition(ac Context, a Addon, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&ac.Cluster.Status.Conditions, metav1.Condition{
+		Type:    ConditionType(a),
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
@@ -0,0 +1,188 @@
+package addon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+)
+
+func TestEnabled(t *testing.T) {
+	a, b := &fakeAddon{name: "A"}, &fakeAddon{name: "B"}
+	available := []Addon{a, b}
+
+	testCases := []struct {
+		desc        string
+		names       []string
+		expected    []Addon
+		expectedErr error
+	}{
+		{
+			desc:     "should keep order of available addons",
+			names:    []string{"b", "A"},
+			expected: []Addon{a, b},
+		},
+		{
+			desc:     "should return no addons for empty list",
+			names:    []string{},
+			expected: []Addon{},
+		},
+		{
+			desc:        "should fail for unknown addon",
+			names:       []string{"C"},
+			expectedErr: errUnknownAddon,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			enabled, err := Enabled(available, tC.names)
+			if tC.expectedErr != nil {
+				assert.ErrorIs(t, err, tC.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.expected, enabled)
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		addons             []*fakeAddon
+		enabled            []int
+		existingConditions []string
+		expectedReady      bool
+		expectErr          bool
+		expectedConditions map[string]metav1.ConditionStatus
+		expectedUninstall  []string
+	}{
+		{
+			desc:               "should install and configure all enabled addons",
+			addons:             []*fakeAddon{{name: "A", ready: true}, {name: "B", ready: true}},
+			enabled:            []int{0, 1},
+			expectedReady:      true,
+			expectedConditions: map[string]metav1.ConditionStatus{"AReady": metav1.ConditionTrue, "BReady": metav1.ConditionTrue},
+		},
+		{
+			desc:               "should stop at the first addon that is not ready",
+			addons:             []*fakeAddon{{name: "A"}, {name: "B", ready: true}},
+			enabled:            []int{0, 1},
+			expectedConditions: map[string]metav1.ConditionStatus{"AReady": metav1.ConditionFalse},
+		},
+		{
+			desc:               "should report pending configuration",
+			addons:             []*fakeAddon{{name: "A", ready: true, configureErr: fmt.Errorf("%w: waiting", ErrPending)}},
+			enabled:            []int{0},
+			expectedConditions: map[string]metav1.ConditionStatus{"AReady": metav1.ConditionFalse},
+		},
+		{
+			desc:               "should fail if install fails",
+			addons:             []*fakeAddon{{name: "A", installErr: errors.New("boom")}},
+			enabled:            []int{0},
+			expectErr:          true,
+			expectedConditions: map[string]metav1.ConditionStatus{"AReady": metav1.ConditionFalse},
+		},
+		{
+			desc:               "should uninstall previously installed addons that are disabled",
+			addons:             []*fakeAddon{{name: "A", ready: true}, {name: "B", ready: true}, {name: "C"}},
+			enabled:            []int{0},
+			existingConditions: []string{"BReady"},
+			expectedReady:      true,
+			expectedConditions: map[string]metav1.ConditionStatus{"AReady": metav1.ConditionTrue},
+			expectedUninstall:  []string{"B"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cluster := &clustersv1alpha1.Cluster{}
+			for _, c := range tC.existingConditions {
+				meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{Type: c, Status: metav1.ConditionTrue, Reason: "AddonReady"})
+			}
+
+			available := []Addon{}
+			for _, a := range tC.addons {
+				available = append(available, a)
+			}
+			enabled := []Addon{}
+			for _, i := range tC.enabled {
+				enabled = append(enabled, tC.addons[i])
+			}
+
+			ready, err := Reconcile(context.Background(), Context{Cluster: cluster, Recorder: events.NewFakeRecorder(10)}, available, enabled)
+			if tC.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tC.expectedReady, ready)
+
+			assert.Len(t, cluster.Status.Conditions, len(tC.expectedConditions))
+			for condType, status := range tC.expectedConditions {
+				assert.True(t, meta.IsStatusConditionPresentAndEqual(cluster.Status.Conditions, condType, status), condType)
+			}
+
+			uninstalled := []string{}
+			for _, a := range tC.addons {
+				if a.uninstalled {
+					uninstalled = append(uninstalled, a.name)
+				}
+			}
+			assert.ElementsMatch(t, tC.expectedUninstall, uninstalled)
+		})
+	}
+}
+
+func TestReconcile_readyEvent(t *testing.T) {
+	cluster := &clustersv1alpha1.Cluster{}
+	recorder := events.NewFakeRecorder(10)
+	ac := Context{Cluster: cluster, Recorder: recorder}
+	a := &fakeAddon{name: "A", ready: true}
+
+	for range 2 {
+		ready, err := Reconcile(context.Background(), ac, []Addon{a}, []Addon{a})
+		assert.NoError(t, err)
+		assert.True(t, ready)
+	}
+
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal AReady Addon A is ready", <-recorder.Events)
+}
+
+var _ Addon = &fakeAddon{}
+
+type fakeAddon struct {
+	name         string
+	ready        bool
+	installErr   error
+	configureErr error
+	uninstalled  bool
+}
+
+func (f *fakeAddon) Name() string {
+	return f.name
+}
+
+func (f *fakeAddon) Install(_ context.Context, _ Context) error {
+	return f.installErr
+}
+
+func (f *fakeAddon) Ready(_ context.Context, _ Context) (bool, error) {
+	return f.ready, nil
+}
+
+func (f *fakeAddon) Configure(_ context.Context, _ Context) error {
+	return f.configureErr
+}
+
+func (f *fakeAddon) Uninstall(_ context.Context, _ Context) error {
+	f.uninstalled = true
+	return nil
+}
@@ -0,0 +1,323 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+)
+
+const (
+	// ConditionBundlesReady reports whether all manifest bundles have been applied and are ready.
+	ConditionBundlesReady = "BundlesReady"
+
+	// LabelBundle is set on all objects of a manifest bundle to the name of the bundle.
+	LabelBundle = "kind.clusters.openmcp.cloud/bundle"
+
+	inlineDir    = "inline"
+	inlineFile   = "manifests.yaml"
+	configMapDir = "configmap"
+)
+
+var (
+	errEmptyBundle = errors.New("bundle has no manifests")
+)
+
+// Reconcile applies the manifest bundles of the ProviderConfig to the kind cluster in order.
+// A bundle is only applied once the objects of all previous bundles are ready, unless waiting is skipped for them.
+// Objects that are no longer part of a bundle and objects of bundles that have been removed are pruned,
+// based on the given status of the previous reconciliation.
+// The state of each bundle is reported in the provider status, the overall state as condition on the Cluster.
+// ConfigMaps referenced by bundles are read with the platform client.
+func Reconcile(ctx context.Context, platform client.Client, ac addon.Context, previous []v1alpha1.BundleStatus) (bool, error) {
+	bundles := ac.Config.Spec.Bundles
+
+	for _, prev := range previous {
+		if slices.ContainsFunc(bundles, func(b v1alpha1.ManifestBundle) bool { return b.Name == prev.Name }) {
+			continue
+		}
+		result := manifest.Result{}
+		if err := manifest.Prune(ctx, ac.Client, nil, toKinds(prev.Kinds), pruneLabels(prev.Name), &result); err != nil {
+			return false, fmt.Errorf("failed to prune removed bundle %s: %w", prev.Name, err)
+		}
+		if result.HasChanges() {
+			ac.Recorder.Eventf(ac.Cluster, nil, corev1.EventTypeNormal, "BundleRemoved", "PruneBundle", "Removed bundle %s: %s", prev.Name, result)
+		}
+	}
+
+	if len(bundles) == 0 {
+		ac.ProviderStatus.Bundles = nil
+		meta.RemoveStatusCondition(&ac.Cluster.Status.Conditions, ConditionBundlesReady)
+		return true, nil
+	}
+
+	statuses := make([]v1alpha1.BundleStatus, 0, len(bundles))
+	var blockedBy string
+	var errs []error
+	for _, b := range bundles {
+		prev := findStatus(previous, b.Name)
+
+		if blockedBy != "" {
+			statuses = append(statuses, v1alpha1.BundleStatus{
+				Name:    b.Name,
+				Message: fmt.Sprintf("Waiting for bundle %s", blockedBy),
+				Objects: prev.Objects,
+				Kinds:   prev.Kinds,
+			})
+			continue
+		}
+
+		status, err := reconcileBundle(ctx, platform, ac, b, prev)
+		statuses = append(statuses, status)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply bundle %s: %w", b.Name, err))
+		}
+		if !status.Ready {
+			blockedBy = b.Name
+		}
+	}
+	ac.ProviderStatus.Bundles = statuses
+
+	reconcileErr := errors.Join(errs...)
+	condition := metav1.Condition{
+		Type:   ConditionBundlesReady,
+		Status: metav1.ConditionTrue,
+		Reason: "BundlesApplied",
+	}
+	switch {
+	case reconcileErr != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "BundleFailed"
+		condition.Message = reconcileErr.Error()
+	case blockedBy != "":
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "BundlePending"
+		condition.Message = fmt.Sprintf("Bundle %s is not ready: %s", blockedBy, findStatus(statuses, blockedBy).Message)
+	}
+	meta.SetStatusCondition(&ac.Cluster.Status.Conditions, condition)
+
+	return condition.Status == metav1.ConditionTrue, reconcileErr
+}
+
+// reconcileBundle renders and applies a single bundle, prunes objects that are no longer part of it
+// and checks the readiness of its objects.
+func reconcileBundle(ctx context.Context, platform client.Client, ac addon.Context, b v1alpha1.ManifestBundle, prev v1alpha1.BundleStatus) (v1alpha1.BundleStatus, error) {
+	status := v1alpha1.BundleStatus{
+		Name:    b.Name,
+		Objects: prev.Objects,
+		Kinds:   prev.Kinds,
+	}
+
+	objs, err := render(ctx, platform, b)
+	if err != nil {
+		status.Message = err.Error()
+		return status, err
+	}
+
+	kinds := manifest.Kinds(objs)
+	status.Objects = len(objs)
+	status.Kinds = mergeKinds(prev.Kinds, kinds)
+
+	applied := manifest.Result{}
+	if err := manifest.Apply(ctx, ac.Client, objs, &applied); err != nil {
+		if meta.IsNoMatchError(err) {
+			// The objects depend on a CustomResourceDefinition that is not established yet.
+			status.Message = err.Error()
+			return status, nil
+		}
+		status.Message = err.Error()
+		return status, err
+	}
+
+	if err := manifest.Prune(ctx, ac.Client, objs, toKinds(status.Kinds), pruneLabels(b.Name), &applied); err != nil {
+		status.Message = err.Error()
+		return status, err
+	}
+	status.Kinds = fromKinds(kinds)
+
+	if applied.HasChanges() {
+		ac.Recorder.Eventf(ac.Cluster, nil, corev1.EventTypeNormal, "BundleApplied", "ApplyBundle", "Applied bundle %s: %s", b.Name, applied)
+	}
+
+	if !b.SkipWait {
+		notReady, err := manifest.NotReady(ctx, ac.Client, objs)
+		if err != nil {
+			status.Message = err.Error()
+			return status, err
+		}
+		if len(notReady) > 0 {
+			status.Message = fmt.Sprintf("Waiting for %d objects to become ready: %s", len(notReady), strings.Join(notReady, ", "))
+			return status, nil
+		}
+	}
+
+	status.Ready = true
+	return status, nil
+}
+
+// render combines the sources of the bundle in an in-memory file system and renders them with kustomize.
+func render(ctx context.Context, platform client.Client, b v1alpha1.ManifestBundle) ([]*unstructured.Unstructured, error) {
+	fs := filesys.MakeFsInMemory()
+	resources := []string{}
+
+	if b.Inline != "" {
+		p := path.Join(inlineDir, inlineFile)
+		if err := fs.WriteFile(p, []byte(b.Inline)); err != nil {
+			return nil, err
+		}
+		resources = append(resources, p)
+	}
+
+	if b.ConfigMapRef != nil {
+		cmResources, err := addConfigMapToFS(ctx, platform, fs, *b.ConfigMapRef)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, cmResources...)
+	}
+
+	if len(resources) == 0 {
+		return nil, errEmptyBundle
+	}
+
+	k := getKustomization(b, resources)
+	jsonBytes, err := json.Marshal(k)
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.WriteFile(konfig.DefaultKustomizationFileName(), jsonBytes); err != nil {
+		return nil, err
+	}
+
+	return manifest.Render(fs, ".")
+}
+
+// addConfigMapToFS writes the keys of the referenced ConfigMap as files and returns the resources to include.
+// If the ConfigMap contains a kustomization, its directory is the only resource.
+func addConfigMapToFS(ctx context.Context, platform client.Client, fs filesys.FileSystem, ref v1alpha1.ConfigMapReference) ([]string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := platform.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}, cm); err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+
+	files := []string{}
+	for name, content := range cm.Data {
+		if err := fs.WriteFile(path.Join(configMapDir, name), []byte(content)); err != nil {
+			return nil, err
+		}
+		files = append(files, name)
+	}
+	slices.Sort(files)
+
+	if slices.ContainsFunc(files, func(name string) bool { return slices.Contains(konfig.RecognizedKustomizationFileNames(), name) }) {
+		return []string{configMapDir}, nil
+	}
+
+	resources := []string{}
+	for _, name := range files {
+		switch path.Ext(name) {
+		case ".yaml", ".yml", ".json":
+			resources = append(resources, path.Join(configMapDir, name))
+		}
+	}
+	return resources, nil
+}
+
+func getKustomization(b v1alpha1.ManifestBundle, resources []string) *types.Kustomization {
+	k := &types.Kustomization{
+		TypeMeta: types.TypeMeta{
+			Kind:       types.KustomizationKind,
+			APIVersion: types.KustomizationVersion,
+		},
+		Resources: resources,
+		Labels: []types.Label{
+			{
+				Pairs: map[string]string{
+					manifest.LabelManagedBy: manifest.LabelManagedByValue,
+					LabelBundle:             b.Name,
+				},
+			},
+		},
+	}
+
+	if b.Kustomization == nil {
+		return k
+	}
+
+	k.Namespace = b.Kustomization.Namespace
+	if len(b.Kustomization.Labels) > 0 {
+		k.Labels = append(k.Labels, types.Label{Pairs: b.Kustomization.Labels})
+	}
+	k.Images = manifest.Images(b.Kustomization.Images)
+	for _, p := range b.Kustomization.Patches {
+		patch := types.Patch{Patch: p.Patch}
+		if p.Target != nil {
+			patch.Target = &types.Selector{
+				ResId: resid.NewResIdWithNamespace(
+					resid.NewGvk(p.Target.Group, p.Target.Version, p.Target.Kind), p.Target.Name, p.Target.Namespace),
+				LabelSelector: p.Target.LabelSelector,
+			}
+		}
+		k.Patches = append(k.Patches, patch)
+	}
+	return k
+}
+
+func pruneLabels(bundleName string) client.MatchingLabels {
+	return client.MatchingLabels{manifest.LabelManagedBy: manifest.LabelManagedByValue, LabelBundle: bundleName}
+}
+
+func findStatus(statuses []v1alpha1.BundleStatus, name string) v1alpha1.BundleStatus {
+	for _, s := range statuses {
+		if s.Name == name {
+			return s
+		}
+	}
+	return v1alpha1.BundleStatus{Name: name}
+}
+
+// mergeKinds returns the previously applied kinds together with the kinds of the current objects,
+// so that objects of kinds that have been removed from the bundle are pruned as well.
+func mergeKinds(prev []metav1.GroupVersionKind, current []schema.GroupVersionKind) []metav1.GroupVersionKind {
+	merged := slices.Clone(prev)
+	for _, gvk := range fromKinds(current) {
+		if !slices.Contains(merged, gvk) {
+			merged = append(merged, gvk)
+		}
+	}
+	return merged
+}
+
+func toKinds(kinds []metav1.GroupVersionKind) []schema.GroupVersionKind {
+	result := make([]schema.GroupVersionKind, 0, len(kinds))
+	for _, gvk := range kinds {
+		result = append(result, schema.GroupVersionKind(gvk))
+	}
+	return result
+}
+
+func fromKinds(kinds []schema.GroupVersionKind) []metav1.GroupVersionKind {
+	result := make([]metav1.GroupVersionKind, 0, len(kinds))
+	for _, gvk := range kinds {
+		result = append(result, metav1.GroupVersionKind(gvk))
+	}
+	return result
+}
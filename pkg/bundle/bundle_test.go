@@ -0,0 +1,203 @@
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+)
+
+const (
+	namespaceYAML = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: team
+`
+	configMapYAML = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: team
+data:
+  key: value
+`
+	deploymentYAML = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: team
+spec:
+  selector:
+    matchLabels:
+      app: app
+  template:
+    metadata:
+      labels:
+        app: app
+    spec:
+      containers:
+      - name: app
+        image: registry.example.com/app:v1
+`
+)
+
+func Test_render(t *testing.T) {
+	platform := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "default"},
+			Data: map[string]string{
+				"namespace.yaml": namespaceYAML,
+				"configmap.yaml": configMapYAML,
+				"README.md":      "not a manifest",
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "kustomized", Namespace: "default"},
+			Data: map[string]string{
+				"kustomization.yaml": "resources:\n- deployment.yaml\nnamePrefix: my-\n",
+				"deployment.yaml":    deploymentYAML,
+			},
+		},
+	).Build()
+
+	testCases := []struct {
+		desc          string
+		bundle        v1alpha1.ManifestBundle
+		expectedNames []string
+		expectedImage string
+		expectErr     bool
+	}{
+		{
+			desc:          "should render inline manifests",
+			bundle:        v1alpha1.ManifestBundle{Name: "inline", Inline: namespaceYAML + "---\n" + configMapYAML},
+			expectedNames: []string{"Namespace/team", "ConfigMap/team/settings"},
+		},
+		{
+			desc:          "should render all manifests of a ConfigMap",
+			bundle:        v1alpha1.ManifestBundle{Name: "plain", ConfigMapRef: &v1alpha1.ConfigMapReference{Name: "plain", Namespace: "default"}},
+			expectedNames: []string{"Namespace/team", "ConfigMap/team/settings"},
+		},
+		{
+			desc: "should render kustomization of a ConfigMap with overlay",
+			bundle: v1alpha1.ManifestBundle{
+				Name:         "kustomized",
+				ConfigMapRef: &v1alpha1.ConfigMapReference{Name: "kustomized", Namespace: "default"},
+				Kustomization: &v1alpha1.KustomizationOverlay{
+					Namespace: "other",
+					Images:    []v1alpha1.ImageOverride{{Name: "registry.example.com/app", NewTag: "v2"}},
+				},
+			},
+			expectedNames: []string{"Deployment/other/my-app"},
+			expectedImage: "registry.example.com/app:v2",
+		},
+		{
+			desc:      "should fail for missing ConfigMap",
+			bundle:    v1alpha1.ManifestBundle{Name: "missing", ConfigMapRef: &v1alpha1.ConfigMapReference{Name: "missing", Namespace: "default"}},
+			expectErr: true,
+		},
+		{
+			desc:      "should fail for empty bundle",
+			bundle:    v1alpha1.ManifestBundle{Name: "empty"},
+			expectErr: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			objs, err := render(context.Background(), platform, tC.bundle)
+			if tC.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			names := []string{}
+			for _, obj := range objs {
+				names = append(names, manifest.ObjectName(obj))
+				assert.Equal(t, tC.bundle.Name, obj.GetLabels()[LabelBundle])
+				if tC.expectedImage != "" && obj.GetKind() == "Deployment" {
+					deployment := &appsv1.Deployment{}
+					assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment))
+					assert.Equal(t, tC.expectedImage, deployment.Spec.Template.Spec.Containers[0].Image)
+				}
+			}
+			assert.ElementsMatch(t, tC.expectedNames, names)
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	kindClient := fake.NewClientBuilder().Build()
+	cluster := &clustersv1alpha1.Cluster{}
+	providerStatus := &v1alpha1.ClusterStatus{}
+	ac := addon.Context{
+		Cluster:        cluster,
+		Client:         kindClient,
+		ProviderStatus: providerStatus,
+		Recorder:       events.NewFakeRecorder(100),
+		Config: &v1alpha1.ProviderConfig{
+			Spec: v1alpha1.ProviderConfigSpec{
+				Bundles: []v1alpha1.ManifestBundle{
+					{Name: "base", Inline: namespaceYAML + "---\n" + configMapYAML},
+					{Name: "app", Inline: deploymentYAML},
+					{Name: "late", Inline: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: late\n  namespace: team\n"},
+				},
+			},
+		},
+	}
+
+	// the deployment never becomes ready in the fake client, so the last bundle is not applied
+	ready, err := Reconcile(ctx, fake.NewClientBuilder().Build(), ac, nil)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+	assert.True(t, meta.IsStatusConditionFalse(cluster.Status.Conditions, ConditionBundlesReady))
+	if assert.Len(t, providerStatus.Bundles, 3) {
+		assert.True(t, providerStatus.Bundles[0].Ready)
+		assert.Equal(t, 2, providerStatus.Bundles[0].Objects)
+		assert.False(t, providerStatus.Bundles[1].Ready)
+		assert.Contains(t, providerStatus.Bundles[1].Message, "Deployment/team/app")
+		assert.False(t, providerStatus.Bundles[2].Ready)
+	}
+	late := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "late", Namespace: "team"}}
+	assert.True(t, apierrors.IsNotFound(kindClient.Get(ctx, client.ObjectKeyFromObject(late), late)))
+
+	// skipping the wait applies the last bundle
+	ac.Config.Spec.Bundles[1].SkipWait = true
+	ready, err = Reconcile(ctx, fake.NewClientBuilder().Build(), ac, providerStatus.Bundles)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionBundlesReady))
+	assert.NoError(t, kindClient.Get(ctx, client.ObjectKeyFromObject(late), late))
+
+	// removing a bundle prunes its objects
+	ac.Config.Spec.Bundles = ac.Config.Spec.Bundles[:2]
+	ready, err = Reconcile(ctx, fake.NewClientBuilder().Build(), ac, providerStatus.Bundles)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+	assert.Len(t, providerStatus.Bundles, 2)
+	assert.True(t, apierrors.IsNotFound(kindClient.Get(ctx, client.ObjectKeyFromObject(late), late)))
+
+	// removing all bundles removes the condition
+	ac.Config.Spec.Bundles = nil
+	ready, err = Reconcile(ctx, fake.NewClientBuilder().Build(), ac, providerStatus.Bundles)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+	assert.Nil(t, meta.FindStatusCondition(cluster.Status.Conditions, ConditionBundlesReady))
+	settings := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "team"}}
+	assert.True(t, apierrors.IsNotFound(kindClient.Get(ctx, client.ObjectKeyFromObject(settings), settings)))
+}
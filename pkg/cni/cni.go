@@ -0,0 +1,147 @@
+package cni
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+)
+
+const (
+	// ConditionCNIReady reports whether the network plugin has been installed and all nodes are ready.
+	ConditionCNIReady = "CNIReady"
+
+	// DefaultPodSubnet is the pod subnet of clusters with a network plugin other than kindnet, if none is configured.
+	DefaultPodSubnet = "10.244.0.0/16"
+)
+
+var (
+	// AnnotationCNI stores the network plugin a cluster has been created with.
+	AnnotationCNI = v1alpha1.SchemeGroupVersion.Group + "/cni"
+)
+
+// Annotate stores the network plugin and pod subnet of the ProviderConfig in the annotations of a cluster that is about
+// to be created, since they cannot be changed afterwards. A pod subnet that has already been assigned to the cluster,
+// e.g. for cross-cluster connectivity, is kept. Clusters with kindnet are not annotated.
+// It returns true if the annotations have been changed.
+func Annotate(cluster *clustersv1alpha1.Cluster, pc *v1alpha1.ProviderConfig) bool {
+	if _, ok := cluster.Annotations[AnnotationCNI]; ok {
+		return false
+	}
+
+	cfg := config(pc)
+	if cfg.Plugin == "" || cfg.Plugin == v1alpha1.CNIPluginKindnet {
+		return false
+	}
+
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, AnnotationCNI, string(cfg.Plugin))
+	if _, ok := cluster.Annotations[kind.AnnotationPodSubnet]; !ok {
+		metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationPodSubnet, cmp.Or(cfg.PodSubnet, DefaultPodSubnet))
+	}
+	return true
+}
+
+// ConfigureCluster disables kindnet and sets the pod subnet if the cluster is annotated with another network plugin.
+func ConfigureCluster(cluster *clustersv1alpha1.Cluster, cfg *kind.ClusterConfig) {
+	plugin, podSubnet := fromCluster(cluster)
+	if plugin == "" {
+		return
+	}
+
+	cfg.DisableDefaultCNI = true
+	cfg.PodSubnet = podSubnet
+}
+
+// Reconcile installs the network plugin the cluster has been created with and waits for it and all nodes to be ready.
+// Nothing is done for clusters with kindnet.
+func Reconcile(ctx context.Context, ac addon.Context) (bool, error) {
+	plugin, podSubnet := fromCluster(ac.Cluster)
+	if plugin == "" {
+		meta.RemoveStatusCondition(&ac.Cluster.Status.Conditions, ConditionCNIReady)
+		return true, nil
+	}
+
+	condition := metav1.Condition{
+		Type:   ConditionCNIReady,
+		Status: metav1.ConditionFalse,
+	}
+
+	notReady, err := reconcile(ctx, ac, plugin, podSubnet)
+	switch {
+	case err != nil:
+		condition.Reason = "InstallFailed"
+		condition.Message = err.Error()
+	case len(notReady) > 0:
+		condition.Reason = "NotReady"
+		condition.Message = fmt.Sprintf("%s is not ready: %s", plugin, strings.Join(notReady, ", "))
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "CNIReady"
+		condition.Message = fmt.Sprintf("%s is ready on all nodes", plugin)
+	}
+	meta.SetStatusCondition(&ac.Cluster.Status.Conditions, condition)
+
+	return condition.Status == metav1.ConditionTrue, err
+}
+
+// reconcile applies the manifests of the network plugin and returns the objects and nodes that are not ready.
+func reconcile(ctx context.Context, ac addon.Context, plugin v1alpha1.CNIPlugin, podSubnet string) ([]string, error) {
+	objs, err := build(plugin, podSubnet, manifest.Images(config(ac.Config).Images))
+	if err != nil {
+		return nil, err
+	}
+
+	result := manifest.Result{}
+	err = manifest.Apply(ctx, ac.Client, objs, &result)
+	if result.HasChanges() {
+		ac.Recorder.Eventf(ac.Cluster, nil, corev1.EventTypeNormal, "CNIApplied", "ApplyCNI", "Applied %s: %s", plugin, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	notReady, err := manifest.NotReady(ctx, ac.Client, objs)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := ac.Client.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	for _, node := range nodes.Items {
+		if !isNodeReady(node) {
+			notReady = append(notReady, "Node/"+node.Name)
+		}
+	}
+	return notReady, nil
+}
+
+func isNodeReady(node corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func fromCluster(cluster *clustersv1alpha1.Cluster) (v1alpha1.CNIPlugin, string) {
+	return v1alpha1.CNIPlugin(cluster.Annotations[AnnotationCNI]), cluster.Annotations[kind.AnnotationPodSubnet]
+}
+
+func config(pc *v1alpha1.ProviderConfig) v1alpha1.CNIConfig {
+	return ptr.Deref(pc.Spec.CNI, v1alpha1.CNIConfig{})
+}
@@ -0,0 +1,218 @@
+package cni
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest/manifesttest"
+)
+
+const (
+	calicoManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: calico-config
+  namespace: kube-system
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: calico-node
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: calico-node
+        image: docker.io/calico/node:v3.28.5
+        env:
+        - name: DATASTORE_TYPE
+          value: kubernetes
+`
+	ciliumManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cilium-config
+  namespace: kube-system
+`
+)
+
+func withTestManifests(t *testing.T) {
+	t.Helper()
+	manifesttest.WithFS(t, manifests, fstest.MapFS{
+		"manifests/calico/install.yaml": {Data: []byte(calicoManifest)},
+		"manifests/cilium/install.yaml": {Data: []byte(ciliumManifest)},
+	})
+}
+
+func TestAnnotate(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		annotations         map[string]string
+		config              *v1alpha1.CNIConfig
+		expectedChanged     bool
+		expectedPlugin      v1alpha1.CNIPlugin
+		expectedPodSubnet   string
+		expectedDisabledCNI bool
+	}{
+		{
+			desc:            "should not annotate cluster with kindnet",
+			config:          nil,
+			expectedChanged: false,
+		},
+		{
+			desc:                "should annotate cluster with default pod subnet",
+			config:              &v1alpha1.CNIConfig{Plugin: v1alpha1.CNIPluginCalico},
+			expectedChanged:     true,
+			expectedPlugin:      v1alpha1.CNIPluginCalico,
+			expectedPodSubnet:   DefaultPodSubnet,
+			expectedDisabledCNI: true,
+		},
+		{
+			desc:                "should keep assigned pod subnet",
+			annotations:         map[string]string{kind.AnnotationPodSubnet: "100.64.0.0/16"},
+			config:              &v1alpha1.CNIConfig{Plugin: v1alpha1.CNIPluginCilium, PodSubnet: "10.100.0.0/16"},
+			expectedChanged:     true,
+			expectedPlugin:      v1alpha1.CNIPluginCilium,
+			expectedPodSubnet:   "100.64.0.0/16",
+			expectedDisabledCNI: true,
+		},
+		{
+			desc:                "should keep annotations of cluster",
+			annotations:         map[string]string{AnnotationCNI: "Cilium", kind.AnnotationPodSubnet: "10.100.0.0/16"},
+			config:              &v1alpha1.CNIConfig{Plugin: v1alpha1.CNIPluginCalico},
+			expectedChanged:     false,
+			expectedPlugin:      v1alpha1.CNIPluginCilium,
+			expectedPodSubnet:   "10.100.0.0/16",
+			expectedDisabledCNI: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cluster := &clustersv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Annotations: tC.annotations}}
+			pc := &v1alpha1.ProviderConfig{Spec: v1alpha1.ProviderConfigSpec{CNI: tC.config}}
+
+			assert.Equal(t, tC.expectedChanged, Annotate(cluster, pc))
+
+			cfg := kind.ClusterConfig{}
+			ConfigureCluster(cluster, &cfg)
+			assert.Equal(t, tC.expectedDisabledCNI, cfg.DisableDefaultCNI)
+			assert.Equal(t, tC.expectedPodSubnet, cfg.PodSubnet)
+
+			plugin, _ := fromCluster(cluster)
+			assert.Equal(t, tC.expectedPlugin, plugin)
+		})
+	}
+}
+
+func Test_build(t *testing.T) {
+	withTestManifests(t)
+
+	objs, err := build(v1alpha1.CNIPluginCalico, "10.100.0.0/16", nil)
+	assert.NoError(t, err)
+	assert.Len(t, objs, 2)
+
+	ds := objs[1]
+	assert.Equal(t, "DaemonSet/kube-system/calico-node", manifest.ObjectName(ds))
+	assert.Equal(t, labelPartOfValue, ds.GetLabels()[manifest.LabelPartOf])
+	containers, _, _ := unstructured.NestedSlice(ds.Object, "spec", "template", "spec", "containers")
+	env := containers[0].(map[string]any)["env"].([]any)
+	assert.Contains(t, env, map[string]any{"name": "CALICO_IPV4POOL_CIDR", "value": "10.100.0.0/16"})
+
+	_, err = build("Flannel", "10.100.0.0/16", nil)
+	assert.ErrorIs(t, err, errUnsupportedPlugin)
+}
+
+// Test_build_embedded guards against building the provider without the generated manifests.
+func Test_build_embedded(t *testing.T) {
+	testCases := []struct {
+		plugin       v1alpha1.CNIPlugin
+		expectedName string
+	}{
+		{plugin: v1alpha1.CNIPluginCalico, expectedName: "DaemonSet/kube-system/calico-node"},
+		{plugin: v1alpha1.CNIPluginCilium, expectedName: "DaemonSet/kube-system/cilium"},
+	}
+	for _, tC := range testCases {
+		t.Run(string(tC.plugin), func(t *testing.T) {
+			objs, err := build(tC.plugin, DefaultPodSubnet, nil)
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, obj := range objs {
+				names = append(names, manifest.ObjectName(obj))
+			}
+			assert.Contains(t, names, tC.expectedName)
+		})
+	}
+
+	objs, err := build(v1alpha1.CNIPluginCalico, DefaultPodSubnet, nil)
+	require.NoError(t, err)
+	for _, obj := range objs {
+		if manifest.ObjectName(obj) != "DaemonSet/kube-system/calico-node" {
+			continue
+		}
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		container := containers[0].(map[string]any)
+		assert.Equal(t, "calico-node", container["name"])
+		assert.Contains(t, container["env"], map[string]any{"name": "CALICO_IPV4POOL_CIDR", "value": DefaultPodSubnet})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	withTestManifests(t)
+	ctx := context.Background()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-control-plane"}}
+	c := fake.NewClientBuilder().WithObjects(node).Build()
+	cluster := &clustersv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		AnnotationCNI:            string(v1alpha1.CNIPluginCilium),
+		kind.AnnotationPodSubnet: DefaultPodSubnet,
+	}}}
+	ac := addon.Context{
+		Cluster:  cluster,
+		Client:   c,
+		Config:   &v1alpha1.ProviderConfig{},
+		Recorder: events.NewFakeRecorder(10),
+	}
+
+	ready, err := Reconcile(ctx, ac)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionCNIReady)
+	assert.Equal(t, "NotReady", condition.Reason)
+	assert.Contains(t, condition.Message, "Node/test-control-plane")
+
+	node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+	assert.NoError(t, c.Status().Update(ctx, node))
+
+	ready, err = Reconcile(ctx, ac)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionCNIReady))
+}
+
+func TestReconcile_kindnet(t *testing.T) {
+	cluster := &clustersv1alpha1.Cluster{}
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{Type: ConditionCNIReady, Status: metav1.ConditionTrue, Reason: "CNIReady"})
+
+	ready, err := Reconcile(context.Background(), addon.Context{Cluster: cluster, Config: &v1alpha1.ProviderConfig{}})
+	assert.NoError(t, err)
+	assert.True(t, ready)
+	assert.Empty(t, cluster.Status.Conditions)
+}
@@ -0,0 +1,63 @@
+package cni
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+)
+
+//go:generate curl -L --create-dirs -o manifests/calico/install.yaml https://raw.githubusercontent.com/projectcalico/calico/v3.28.5/manifests/calico.yaml
+//go:generate sh -c "mkdir -p manifests/cilium && helm template cilium cilium --repo https://helm.cilium.io --version 1.17.5 --namespace kube-system --set ipam.mode=kubernetes --set operator.replicas=1 --set image.pullPolicy=IfNotPresent --set hubble.tls.auto.method=cronJob > manifests/cilium/install.yaml"
+
+var (
+	//go:embed all:manifests
+	embedded embed.FS
+
+	manifests = &manifest.Source{FS: embedded, Dir: "manifests", Package: "./pkg/cni/..."}
+
+	errUnsupportedPlugin = errors.New("unsupported CNI plugin")
+)
+
+const (
+	labelPartOfValue = "cni"
+
+	resourceCalico = "calico/install.yaml"
+	resourceCilium = "cilium/install.yaml"
+
+	// calicoPoolCIDRPatch sets the CIDR of the default IP pool of Calico to the pod subnet of the cluster.
+	// It is not set in the manifest, so Calico would default to 192.168.0.0/16.
+	calicoPoolCIDRPatch = `[{"op": "add", "path": "/spec/template/spec/containers/0/env/-", "value": {"name": "CALICO_IPV4POOL_CIDR", "value": %q}}]`
+)
+
+// build renders the manifests of the given network plugin for the given pod subnet.
+func build(plugin v1alpha1.CNIPlugin, podSubnet string, images []types.Image) ([]*unstructured.Unstructured, error) {
+	var resource string
+	var patches []types.Patch
+	switch plugin {
+	case v1alpha1.CNIPluginCalico:
+		resource = resourceCalico
+		patches = append(patches, types.Patch{
+			Patch: fmt.Sprintf(calicoPoolCIDRPatch, podSubnet),
+			Target: &types.Selector{
+				ResId: resid.NewResIdWithNamespace(resid.NewGvk("apps", "v1", "DaemonSet"), "calico-node", "kube-system"),
+			},
+		})
+	case v1alpha1.CNIPluginCilium:
+		resource = resourceCilium
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedPlugin, plugin)
+	}
+
+	return manifests.Render(resource, manifest.RenderOptions{
+		PartOf:  labelPartOfValue,
+		Images:  images,
+		Patches: patches,
+	})
+}
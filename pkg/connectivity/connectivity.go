@@ -0,0 +1,233 @@
+package connectivity
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+const (
+	// ConditionConnectivityReady reports whether the routes to the other clusters of the connectivity group exist.
+	ConditionConnectivityReady = "ConnectivityReady"
+)
+
+var (
+	// DefaultGroupLabel is the label of the Clusters whose value defines the connectivity group, if none is configured.
+	DefaultGroupLabel = v1alpha1.SchemeGroupVersion.Group + "/connectivity-group"
+)
+
+// member is a kind cluster of a connectivity group.
+type member struct {
+	kindName      string
+	podSubnet     net.IPNet
+	serviceSubnet net.IPNet
+}
+
+// AssignSubnets allocates pod and service subnets for a cluster that is about to be created if connectivity is enabled,
+// and stores them in the annotations of the cluster, since they cannot be changed afterwards.
+// It returns true if the annotations have been changed.
+func AssignSubnets(ctx context.Context, c client.Client, cluster *clustersv1alpha1.Cluster, pc *v1alpha1.ProviderConfig) (bool, error) {
+	if !config(pc).Enabled {
+		return false, nil
+	}
+	if _, ok := cluster.Annotations[kind.AnnotationServiceSubnet]; ok {
+		return false, nil
+	}
+
+	podSubnet, serviceSubnet, err := kind.NextAvailableClusterSubnets(ctx, c)
+	if err != nil {
+		return false, err
+	}
+
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationPodSubnet, podSubnet.String())
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, kind.AnnotationServiceSubnet, serviceSubnet.String())
+	return true, nil
+}
+
+// ConfigureCluster sets the pod and service subnets if they have been assigned to the cluster.
+func ConfigureCluster(cluster *clustersv1alpha1.Cluster, cfg *kind.ClusterConfig) {
+	if _, ok := cluster.Annotations[kind.AnnotationServiceSubnet]; !ok {
+		return
+	}
+	cfg.PodSubnet = cluster.Annotations[kind.AnnotationPodSubnet]
+	cfg.ServiceSubnet = cluster.Annotations[kind.AnnotationServiceSubnet]
+}
+
+// Reconcile installs static routes between the node containers of the clusters in the connectivity group of the
+// cluster, so that pods and services of one cluster can be reached from the others. The routes of all members of the
+// group are updated, so that clusters joining or leaving the group are picked up by the other members, too.
+// The subnets of the cluster are reported in the provider status. Nothing is done for clusters without assigned subnets.
+func Reconcile(ctx context.Context, c client.Client, ac addon.Context) (bool, error) {
+	self, ok, err := memberFromCluster(ac.Cluster, ac.KindName)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		meta.RemoveStatusCondition(&ac.Cluster.Status.Conditions, ConditionConnectivityReady)
+		return true, nil
+	}
+	ac.ProviderStatus.PodSubnet = self.podSubnet.String()
+	ac.ProviderStatus.ServiceSubnet = self.serviceSubnet.String()
+
+	condition := metav1.Condition{
+		Type:   ConditionConnectivityReady,
+		Status: metav1.ConditionTrue,
+		Reason: "RoutesConfigured",
+	}
+
+	peers, err := reconcile(ctx, c, ac, self)
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RoutingFailed"
+		condition.Message = err.Error()
+	} else {
+		condition.Message = fmt.Sprintf("Routes to %d other clusters configured", peers)
+	}
+	meta.SetStatusCondition(&ac.Cluster.Status.Conditions, condition)
+
+	return condition.Status == metav1.ConditionTrue, err
+}
+
+// reconcile ensures the routes on the nodes of all members of the group and returns the number of peers the cluster
+// has routes to. Members without nodes are not routed to.
+func reconcile(ctx context.Context, c client.Client, ac addon.Context, self member) (int, error) {
+	members := []member{self}
+	label := cmp.Or(config(ac.Config).GroupLabel, DefaultGroupLabel)
+	if group := ac.Cluster.Labels[label]; group != "" {
+		peers, err := listPeers(ctx, c, ac.Cluster, label, group)
+		if err != nil {
+			return 0, err
+		}
+		members = append(members, peers...)
+	}
+
+	gateways := map[string]net.IP{}
+	for _, m := range members {
+		ips, err := kind.NodeIPs(ctx, m.kindName)
+		if err != nil {
+			return 0, err
+		}
+		if len(ips) > 0 {
+			gateways[m.kindName] = ips[0]
+		}
+	}
+
+	routes := groupRoutes(members, gateways)
+	for _, m := range members {
+		if _, ok := gateways[m.kindName]; !ok {
+			continue
+		}
+		if err := kind.EnsureRoutes(ctx, m.kindName, routes[m.kindName]); err != nil {
+			return 0, fmt.Errorf("failed to configure routes of cluster %s: %w", m.kindName, err)
+		}
+	}
+	if _, ok := gateways[self.kindName]; !ok {
+		return 0, nil
+	}
+	return len(gateways) - 1, nil
+}
+
+// listPeers returns the other clusters of the connectivity group that have subnets assigned, have been created and run
+// in the same Docker network on the same Docker host as the given cluster.
+func listPeers(ctx context.Context, c client.Client, cluster *clustersv1alpha1.Cluster, label, group string) ([]member, error) {
+	clusters := &clustersv1alpha1.ClusterList{}
+	if err := c.List(ctx, clusters, client.MatchingLabels{label: group}); err != nil {
+		return nil, err
+	}
+
+	network := kind.NetworkFromCluster(cluster)
+	host, err := kind.DockerHostFromCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+	peers := []member{}
+	for _, other := range clusters.Items {
+		if other.UID == cluster.UID || !other.DeletionTimestamp.IsZero() || kind.NetworkFromCluster(&other) != network {
+			continue
+		}
+		otherHost, err := kind.DockerHostFromCluster(&other)
+		if err != nil {
+			return nil, err
+		}
+		if otherHost != host {
+			continue
+		}
+		name, err := kindClusterName(&other)
+		if err != nil {
+			return nil, err
+		}
+		m, ok, err := memberFromCluster(&other, name)
+		if err != nil {
+			return nil, err
+		}
+		if ok && name != "" {
+			peers = append(peers, m)
+		}
+	}
+	return peers, nil
+}
+
+// groupRoutes returns the routes of each member of the group to the subnets of the other members via the first node
+// of the other member. Members without nodes are skipped.
+func groupRoutes(members []member, gateways map[string]net.IP) map[string][]kind.Route {
+	routes := map[string][]kind.Route{}
+	for _, m := range members {
+		routes[m.kindName] = []kind.Route{}
+		for _, other := range members {
+			gateway, ok := gateways[other.kindName]
+			if other.kindName == m.kindName || !ok {
+				continue
+			}
+			routes[m.kindName] = append(routes[m.kindName],
+				kind.Route{Destination: other.podSubnet, Gateway: gateway},
+				kind.Route{Destination: other.serviceSubnet, Gateway: gateway},
+			)
+		}
+		slices.SortFunc(routes[m.kindName], func(a, b kind.Route) int {
+			return cmp.Compare(a.Destination.String(), b.Destination.String())
+		})
+	}
+	return routes
+}
+
+// memberFromCluster returns the cluster as member of a connectivity group if it has subnets assigned.
+func memberFromCluster(cluster *clustersv1alpha1.Cluster, kindName string) (member, bool, error) {
+	podSubnet, serviceSubnet, err := kind.ClusterSubnetsFromCluster(cluster)
+	if err != nil {
+		return member{}, false, err
+	}
+	if podSubnet == nil || serviceSubnet == nil {
+		return member{}, false, nil
+	}
+	return member{kindName: kindName, podSubnet: *podSubnet, serviceSubnet: *serviceSubnet}, true, nil
+}
+
+// kindClusterName returns the name of the kind cluster from the provider status of the cluster.
+// It is empty if the kind cluster has not been created yet.
+func kindClusterName(cluster *clustersv1alpha1.Cluster) (string, error) {
+	if cluster.Status.ProviderStatus == nil || len(cluster.Status.ProviderStatus.Raw) == 0 {
+		return "", nil
+	}
+	status := v1alpha1.ClusterStatus{}
+	if err := cluster.Status.GetProviderStatus(&status); err != nil {
+		return "", err
+	}
+	return status.KindClusterName, nil
+}
+
+func config(pc *v1alpha1.ProviderConfig) v1alpha1.ConnectivityConfig {
+	return ptr.Deref(pc.Spec.Connectivity, v1alpha1.ConnectivityConfig{})
+}
@@ -0,0 +1,138 @@
+package connectivity
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+func mustParseCIDR(s string) net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return *n
+}
+
+func newCluster(name, group string, annotations map[string]string, kindName string) *clustersv1alpha1.Cluster {
+	cluster := &clustersv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   "default",
+		UID:         types.UID(name),
+		Labels:      map[string]string{DefaultGroupLabel: group},
+		Annotations: annotations,
+	}}
+	if kindName != "" {
+		_ = cluster.Status.SetProviderStatus(v1alpha1.ClusterStatus{KindClusterName: kindName})
+	}
+	return cluster
+}
+
+func subnets(pod, service string) map[string]string {
+	return map[string]string{kind.AnnotationPodSubnet: pod, kind.AnnotationServiceSubnet: service}
+}
+
+func TestAssignSubnets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clustersv1alpha1.AddToScheme(scheme)
+	existing := newCluster("a", "", subnets("100.64.0.0/16", "100.96.0.0/16"), "")
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+	ctx := context.Background()
+
+	cluster := newCluster("b", "", nil, "")
+	changed, err := AssignSubnets(ctx, c, cluster, &v1alpha1.ProviderConfig{})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Empty(t, cluster.Annotations)
+
+	pc := &v1alpha1.ProviderConfig{Spec: v1alpha1.ProviderConfigSpec{Connectivity: &v1alpha1.ConnectivityConfig{Enabled: true}}}
+	changed, err = AssignSubnets(ctx, c, cluster, pc)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, subnets("100.65.0.0/16", "100.97.0.0/16"), cluster.Annotations)
+
+	changed, err = AssignSubnets(ctx, c, cluster, pc)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	cfg := kind.ClusterConfig{PodSubnet: "10.244.0.0/16"}
+	ConfigureCluster(cluster, &cfg)
+	assert.Equal(t, "100.65.0.0/16", cfg.PodSubnet)
+	assert.Equal(t, "100.97.0.0/16", cfg.ServiceSubnet)
+}
+
+func Test_listPeers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clustersv1alpha1.AddToScheme(scheme)
+
+	self := newCluster("self", "blue", subnets("100.64.0.0/16", "100.96.0.0/16"), "self")
+	isolated := newCluster("isolated", "blue", subnets("100.67.0.0/16", "100.99.0.0/16"), "isolated")
+	isolated.Annotations[kind.AnnotationNetwork] = "kind-isolated"
+	remote := newCluster("remote", "blue", subnets("100.69.0.0/16", "100.101.0.0/16"), "")
+	_ = remote.Status.SetProviderStatus(v1alpha1.ClusterStatus{KindClusterName: "remote", DockerHost: "remote"})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		self,
+		newCluster("peer", "blue", subnets("100.65.0.0/16", "100.97.0.0/16"), "peer.1234"),
+		newCluster("other-group", "red", subnets("100.66.0.0/16", "100.98.0.0/16"), "other-group"),
+		newCluster("not-created", "blue", subnets("100.68.0.0/16", "100.100.0.0/16"), ""),
+		newCluster("no-subnets", "blue", nil, "no-subnets"),
+		isolated,
+		remote,
+	).Build()
+
+	peers, err := listPeers(context.Background(), c, self, DefaultGroupLabel, "blue")
+	assert.NoError(t, err)
+	assert.Equal(t, []member{{
+		kindName:      "peer.1234",
+		podSubnet:     mustParseCIDR("100.65.0.0/16"),
+		serviceSubnet: mustParseCIDR("100.97.0.0/16"),
+	}}, peers)
+}
+
+func Test_groupRoutes(t *testing.T) {
+	members := []member{
+		{kindName: "a", podSubnet: mustParseCIDR("100.64.0.0/16"), serviceSubnet: mustParseCIDR("100.96.0.0/16")},
+		{kindName: "b", podSubnet: mustParseCIDR("100.65.0.0/16"), serviceSubnet: mustParseCIDR("100.97.0.0/16")},
+		{kindName: "c", podSubnet: mustParseCIDR("100.66.0.0/16"), serviceSubnet: mustParseCIDR("100.98.0.0/16")},
+	}
+	gateways := map[string]net.IP{
+		"a": net.ParseIP("172.18.0.2"),
+		"b": net.ParseIP("172.18.0.3"),
+	}
+
+	routes := groupRoutes(members, gateways)
+	assert.Equal(t, []kind.Route{
+		{Destination: mustParseCIDR("100.65.0.0/16"), Gateway: net.ParseIP("172.18.0.3")},
+		{Destination: mustParseCIDR("100.97.0.0/16"), Gateway: net.ParseIP("172.18.0.3")},
+	}, routes["a"])
+	assert.Equal(t, []kind.Route{
+		{Destination: mustParseCIDR("100.64.0.0/16"), Gateway: net.ParseIP("172.18.0.2")},
+		{Destination: mustParseCIDR("100.96.0.0/16"), Gateway: net.ParseIP("172.18.0.2")},
+	}, routes["b"])
+	assert.Len(t, routes["c"], 4)
+}
+
+func TestReconcile_withoutSubnets(t *testing.T) {
+	cluster := &clustersv1alpha1.Cluster{}
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{Type: ConditionConnectivityReady, Status: metav1.ConditionTrue, Reason: "RoutesConfigured"})
+	status := &v1alpha1.ClusterStatus{}
+
+	ready, err := Reconcile(context.Background(), nil, addon.Context{Cluster: cluster, Config: &v1alpha1.ProviderConfig{}, ProviderStatus: status})
+	assert.NoError(t, err)
+	assert.True(t, ready)
+	assert.Empty(t, cluster.Status.Conditions)
+	assert.Empty(t, status.PodSubnet)
+}
@@ -0,0 +1,227 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	watchapi "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+const (
+	// AddonName is the name of the DNS addon.
+	AddonName = "DNS"
+
+	// watchRetryInterval is the time to wait before a failed or closed watch of the services is restarted.
+	watchRetryInterval = 10 * time.Second
+	// syncDelay is the time without further service events after which the records are updated, so that a burst of
+	// events, e.g. the ADDED events of all services when a watch is established, results in a single update.
+	syncDelay = time.Second
+)
+
+// NewAddon returns the addon that runs a DNS server container, which is shared by all clusters. It resolves
+// <service>.<namespace>.<cluster>.<cluster namespace>.<domain> to the IPs of the LoadBalancer services of the kind clusters.
+// The services of each kind cluster are watched, so that the records follow changes without a reconciliation.
+func NewAddon() addon.Addon {
+	return &dnsAddon{
+		watches:       map[string]watch{},
+		setRecords:    kind.SetDNSRecords,
+		deleteRecords: kind.DeleteDNSRecords,
+	}
+}
+
+var _ addon.Addon = &dnsAddon{}
+var _ addon.Finalizer = &dnsAddon{}
+
+type dnsAddon struct {
+	lock    sync.Mutex
+	watches map[string]watch
+
+	setRecords    func(ctx context.Context, kindName string, records []kind.DNSRecord) error
+	deleteRecords func(ctx context.Context, kindName string) error
+}
+
+// watch is a running watch of the services of a kind cluster.
+type watch struct {
+	domain string
+	cancel context.CancelFunc
+}
+
+// Name implements addon.Addon.
+func (a *dnsAddon) Name() string {
+	return AddonName
+}
+
+// Install implements addon.Addon.
+func (a *dnsAddon) Install(ctx context.Context, ac addon.Context) error {
+	return kind.EnsureDNS(ctx, dnsConfig(ac.Config))
+}
+
+// Ready implements addon.Addon.
+func (a *dnsAddon) Ready(_ context.Context, _ addon.Context) (bool, error) {
+	return true, nil
+}
+
+// Configure implements addon.Addon.
+// It updates the records of the kind cluster and makes sure its services are watched.
+func (a *dnsAddon) Configure(ctx context.Context, ac addon.Context) error {
+	cfg := dnsConfig(ac.Config)
+	domain := cfg.ClusterDomain(ac.Cluster.Name, ac.Cluster.Namespace)
+	if err := a.sync(ctx, ac.Client, ac.KindName, domain); err != nil {
+		return err
+	}
+
+	if c, ok := ac.Client.(client.WithWatch); ok {
+		a.startWatch(ctx, ac.KindName, domain, c)
+	}
+
+	ac.ProviderStatus.DNS = &v1alpha1.DNSStatus{
+		Server: cfg.Address(ctx),
+		Domain: domain,
+	}
+	return nil
+}
+
+// Uninstall implements addon.Addon.
+// The DNS server container is kept, since it is shared by all clusters.
+func (a *dnsAddon) Uninstall(ctx context.Context, ac addon.Context) error {
+	a.stopWatch(ac.KindName)
+	return a.deleteRecords(ctx, ac.KindName)
+}
+
+// Finalize implements addon.Finalizer.
+func (a *dnsAddon) Finalize(ctx context.Context, kindName string) error {
+	a.stopWatch(kindName)
+	return a.deleteRecords(ctx, kindName)
+}
+
+// sync replaces the records of the kind cluster with the ones of its current LoadBalancer services.
+func (a *dnsAddon) sync(ctx context.Context, c client.Client, kindName, domain string) error {
+	services := &corev1.ServiceList{}
+	if err := c.List(ctx, services); err != nil {
+		return err
+	}
+	return a.setRecords(ctx, kindName, records(services.Items, domain))
+}
+
+// startWatch starts watching the services of the kind cluster unless they are already watched for the same domain.
+// The watch outlives the reconciliation, but keeps the values of its context like the Docker endpoint of the cluster.
+func (a *dnsAddon) startWatch(ctx context.Context, kindName, domain string, c client.WithWatch) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if w, ok := a.watches[kindName]; ok {
+		if w.domain == domain {
+			return
+		}
+		w.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	a.watches[kindName] = watch{domain: domain, cancel: cancel}
+	go a.watchServices(ctx, c, kindName, domain)
+}
+
+func (a *dnsAddon) stopWatch(kindName string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if w, ok := a.watches[kindName]; ok {
+		w.cancel()
+		delete(a.watches, kindName)
+	}
+}
+
+// watchServices updates the records of the kind cluster whenever services change, until the context is canceled.
+// Events are coalesced by syncDelay. Failed or closed watches are restarted after watchRetryInterval.
+func (a *dnsAddon) watchServices(ctx context.Context, c client.WithWatch, kindName, domain string) {
+	log := logf.Log.WithName("dns").WithValues("kindCluster", kindName)
+	for {
+		w, err := c.Watch(ctx, &corev1.ServiceList{})
+		if err != nil {
+			log.Error(err, "Failed to watch services")
+		} else {
+			// Sync once the watch is established, so that changes before it are not missed.
+			if err := a.sync(ctx, c, kindName, domain); err != nil {
+				log.Error(err, "Failed to update DNS records")
+			}
+			events := w.ResultChan()
+			for range events {
+				open := coalesce(events)
+				if ctx.Err() != nil {
+					break
+				}
+				if err := a.sync(ctx, c, kindName, domain); err != nil {
+					log.Error(err, "Failed to update DNS records")
+				}
+				if !open {
+					break
+				}
+			}
+			w.Stop()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
+
+// coalesce discards the events until none has been received for syncDelay.
+// It returns false if the channel has been closed.
+func coalesce(events <-chan watchapi.Event) bool {
+	timer := time.NewTimer(syncDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return false
+			}
+			timer.Reset(syncDelay)
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// records returns a record for each ingress IP of the given LoadBalancer services.
+func records(services []corev1.Service, domain string) []kind.DNSRecord {
+	records := []kind.DNSRecord{}
+	for _, svc := range services {
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			ip := net.ParseIP(ingress.IP)
+			if ip == nil {
+				continue
+			}
+			records = append(records, kind.DNSRecord{
+				Name: svc.Name + "." + svc.Namespace + "." + domain,
+				IP:   ip,
+			})
+		}
+	}
+	return records
+}
+
+func dnsConfig(pc *v1alpha1.ProviderConfig) kind.DNSConfig {
+	cfg := ptr.Deref(pc.Spec.DNS, v1alpha1.DNSConfig{})
+	return kind.DNSConfig{
+		Image:    cfg.Image,
+		HostPort: cfg.HostPort,
+		Domain:   cfg.Domain,
+	}
+}
@@ -0,0 +1,114 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	watchapi "k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+// fakeRecords stores the records instead of writing them to the DNS server container.
+type fakeRecords struct {
+	lock    sync.Mutex
+	records map[string][]kind.DNSRecord
+}
+
+func newTestAddon(f *fakeRecords) *dnsAddon {
+	a := NewAddon().(*dnsAddon)
+	a.setRecords = func(_ context.Context, kindName string, records []kind.DNSRecord) error {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		f.records[kindName] = records
+		return nil
+	}
+	a.deleteRecords = func(_ context.Context, kindName string) error {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		delete(f.records, kindName)
+		return nil
+	}
+	return a
+}
+
+func (f *fakeRecords) get(kindName string) []kind.DNSRecord {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.records[kindName]
+}
+
+func newService(name string, svcType corev1.ServiceType, ips ...string) *corev1.Service {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Type: svcType},
+	}
+	for _, ip := range ips {
+		svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, corev1.LoadBalancerIngress{IP: ip})
+	}
+	return svc
+}
+
+func Test_records(t *testing.T) {
+	services := []corev1.Service{
+		*newService("web", corev1.ServiceTypeLoadBalancer, "172.18.255.1", "172.18.255.2"),
+		*newService("pending", corev1.ServiceTypeLoadBalancer),
+		*newService("internal", corev1.ServiceTypeClusterIP),
+	}
+
+	assert.Equal(t, []kind.DNSRecord{
+		{Name: "web.default.test.default.kind.local", IP: net.ParseIP("172.18.255.1")},
+		{Name: "web.default.test.default.kind.local", IP: net.ParseIP("172.18.255.2")},
+	}, records(services, "test.default.kind.local"))
+}
+
+func TestConfigure(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeRecords{records: map[string][]kind.DNSRecord{}}
+	a := newTestAddon(f)
+
+	c := fake.NewClientBuilder().WithObjects(newService("web", corev1.ServiceTypeLoadBalancer, "172.18.255.1")).Build()
+	status := &v1alpha1.ClusterStatus{}
+	ac := addon.Context{
+		Cluster:        &clustersv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+		KindName:       "test.1234",
+		Client:         c,
+		Config:         &v1alpha1.ProviderConfig{},
+		ProviderStatus: status,
+	}
+
+	assert.NoError(t, a.Configure(ctx, ac))
+	assert.Equal(t, &v1alpha1.DNSStatus{Server: "127.0.0.1:1053", Domain: "test.default.kind.local"}, status.DNS)
+	assert.Equal(t, []kind.DNSRecord{{Name: "web.default.test.default.kind.local", IP: net.ParseIP("172.18.255.1")}}, f.get("test.1234"))
+
+	// changes of services are picked up by the watch
+	assert.NoError(t, c.Create(ctx, newService("api", corev1.ServiceTypeLoadBalancer, "172.18.255.2")))
+	assert.Eventually(t, func() bool { return len(f.get("test.1234")) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, a.Finalize(ctx, "test.1234"))
+	assert.Empty(t, a.watches)
+	assert.Nil(t, f.get("test.1234"))
+}
+
+func Test_coalesce(t *testing.T) {
+	events := make(chan watchapi.Event, 3)
+	for range 3 {
+		events <- watchapi.Event{Type: watchapi.Added}
+	}
+	assert.True(t, coalesce(events))
+	assert.Empty(t, events)
+
+	close(events)
+	assert.False(t, coalesce(events))
+}
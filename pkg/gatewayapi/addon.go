@@ -0,0 +1,151 @@
+package gatewayapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+)
+
+const (
+	// AddonName is the name of the Gateway API addon.
+	AddonName = "GatewayAPI"
+
+	// ConditionGatewayClassReady reports whether the GatewayClass has been accepted by the implementation.
+	ConditionGatewayClassReady = "GatewayClassReady"
+
+	// GatewayClassName is the name of the GatewayClass that is created for the implementation.
+	GatewayClassName = "kind"
+)
+
+// NewAddon returns the addon that installs the Gateway API CRDs and Envoy Gateway as implementation.
+func NewAddon() addon.Addon {
+	return &gatewayAPIAddon{}
+}
+
+var _ addon.Addon = &gatewayAPIAddon{}
+
+type gatewayAPIAddon struct{}
+
+// Name implements addon.Addon.
+func (a *gatewayAPIAddon) Name() string {
+	return AddonName
+}
+
+// Install implements addon.Addon.
+func (a *gatewayAPIAddon) Install(ctx context.Context, ac addon.Context) error {
+	cfg := config(ac.Config)
+	objs, err := build(cfg.Channel, manifest.Images(cfg.Images))
+	if err != nil {
+		return err
+	}
+
+	result := manifest.Result{}
+	err = manifest.Apply(ctx, ac.Client, objs, &result)
+	if err == nil {
+		err = manifest.Prune(ctx, ac.Client, objs, manifest.Kinds(objs), pruneLabels, &result)
+	}
+	if result.HasChanges() {
+		ac.Recorder.Eventf(ac.Cluster, nil, corev1.EventTypeNormal, "GatewayAPIApplied", "ApplyGatewayAPI", "Applied Gateway API: %s", result)
+	}
+	return err
+}
+
+// Ready implements addon.Addon.
+func (a *gatewayAPIAddon) Ready(ctx context.Context, ac addon.Context) (bool, error) {
+	cfg := config(ac.Config)
+	objs, err := build(cfg.Channel, manifest.Images(cfg.Images))
+	if err != nil {
+		return false, err
+	}
+
+	notReady, err := manifest.NotReady(ctx, ac.Client, objs)
+	return len(notReady) == 0, err
+}
+
+// Configure implements addon.Addon.
+// It creates the GatewayClass of the implementation and waits for it to be accepted.
+func (a *gatewayAPIAddon) Configure(ctx context.Context, ac addon.Context) error {
+	gc := newGatewayClass()
+	gc.Object["spec"] = map[string]any{
+		"controllerName": controllerName,
+	}
+	if err := manifest.Apply(ctx, ac.Client, []*unstructured.Unstructured{gc}, &manifest.Result{}); err != nil {
+		return err
+	}
+
+	if err := ac.Client.Get(ctx, client.ObjectKeyFromObject(gc), gc); err != nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:    ConditionGatewayClassReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  "GatewayClassNotAccepted",
+		Message: fmt.Sprintf("GatewayClass %s has not been accepted by %s", GatewayClassName, controllerName),
+	}
+	if isAccepted(gc) {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "GatewayClassAccepted"
+		condition.Message = fmt.Sprintf("GatewayClass %s has been accepted by %s", GatewayClassName, controllerName)
+	}
+	meta.SetStatusCondition(&ac.Cluster.Status.Conditions, condition)
+
+	if condition.Status != metav1.ConditionTrue {
+		return fmt.Errorf("%w: %s", addon.ErrPending, condition.Message)
+	}
+	return nil
+}
+
+// Uninstall implements addon.Addon.
+func (a *gatewayAPIAddon) Uninstall(ctx context.Context, ac addon.Context) error {
+	if err := ac.Client.Delete(ctx, newGatewayClass()); client.IgnoreNotFound(err) != nil && !meta.IsNoMatchError(err) {
+		return err
+	}
+
+	cfg := config(ac.Config)
+	objs, err := build(cfg.Channel, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := manifest.Prune(ctx, ac.Client, nil, manifest.Kinds(objs), pruneLabels, &manifest.Result{}); err != nil {
+		return err
+	}
+
+	meta.RemoveStatusCondition(&ac.Cluster.Status.Conditions, ConditionGatewayClassReady)
+	return nil
+}
+
+func newGatewayClass() *unstructured.Unstructured {
+	gc := &unstructured.Unstructured{}
+	gc.SetGroupVersionKind(gatewayClassGVK)
+	gc.SetName(GatewayClassName)
+	gc.SetLabels(map[string]string{
+		manifest.LabelManagedBy: manifest.LabelManagedByValue,
+		manifest.LabelPartOf:    labelPartOfValue,
+	})
+	return gc
+}
+
+func isAccepted(gc *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(gc.Object, "status", "conditions")
+	return slices.ContainsFunc(conditions, func(c any) bool {
+		m, ok := c.(map[string]any)
+		return ok && m["type"] == "Accepted" && m["status"] == "True"
+	})
+}
+
+func config(pc *v1alpha1.ProviderConfig) v1alpha1.GatewayAPIConfig {
+	return ptr.Deref(pc.Spec.GatewayAPI, v1alpha1.GatewayAPIConfig{})
+}
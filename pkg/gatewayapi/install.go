@@ -0,0 +1,70 @@
+package gatewayapi
+
+import (
+	"embed"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+)
+
+//go:generate curl -L --create-dirs -o manifests/gateway-api/standard-install.yaml https://github.com/kubernetes-sigs/gateway-api/releases/download/v1.3.0/standard-install.yaml
+//go:generate curl -L --create-dirs -o manifests/gateway-api/experimental-install.yaml https://github.com/kubernetes-sigs/gateway-api/releases/download/v1.3.0/experimental-install.yaml
+//go:generate curl -L --create-dirs -o manifests/envoy-gateway/install.yaml https://github.com/envoyproxy/gateway/releases/download/v1.4.1/install.yaml
+
+var (
+	//go:embed all:manifests
+	embedded embed.FS
+
+	manifests = &manifest.Source{FS: embedded, Dir: "manifests", Package: "./pkg/gatewayapi/..."}
+
+	gatewayClassGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "GatewayClass"}
+
+	pruneLabels = client.MatchingLabels{manifest.LabelManagedBy: manifest.LabelManagedByValue, manifest.LabelPartOf: labelPartOfValue}
+)
+
+const (
+	labelPartOfValue = "gateway-api"
+
+	resourceStandardCRDs     = "gateway-api/standard-install.yaml"
+	resourceExperimentalCRDs = "gateway-api/experimental-install.yaml"
+	resourceImplementation   = "envoy-gateway/install.yaml"
+
+	// controllerName is the controller name of the implementation in the GatewayClass.
+	controllerName = "gateway.envoyproxy.io/gatewayclass-controller"
+)
+
+// build renders the Gateway API CRDs of the given channel together with the implementation.
+// The Gateway API CRDs that are bundled with the implementation are dropped in favor of the CRDs of the channel.
+func build(channel v1alpha1.GatewayAPIChannel, images []types.Image) ([]*unstructured.Unstructured, error) {
+	crdsResource := resourceStandardCRDs
+	if channel == v1alpha1.GatewayAPIChannelExperimental {
+		crdsResource = resourceExperimentalCRDs
+	}
+
+	objs, err := manifests.Render(crdsResource, manifest.RenderOptions{PartOf: labelPartOfValue})
+	if err != nil {
+		return nil, err
+	}
+
+	implementation, err := manifests.Render(resourceImplementation, manifest.RenderOptions{PartOf: labelPartOfValue, Images: images})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range implementation {
+		if !isGatewayAPICRD(obj) {
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+func isGatewayAPICRD(obj *unstructured.Unstructured) bool {
+	return obj.GetKind() == "CustomResourceDefinition" && strings.HasSuffix(obj.GetName(), "."+gatewayClassGVK.Group)
+}
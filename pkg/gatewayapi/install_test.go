@@ -0,0 +1,156 @@
+package gatewayapi
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/kustomize/api/types"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest/manifesttest"
+)
+
+const (
+	gatewayClassesCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: gatewayclasses.gateway.networking.k8s.io
+`
+	tcpRoutesCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tcproutes.gateway.networking.k8s.io
+`
+	implementation = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: gatewayclasses.gateway.networking.k8s.io
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: envoyproxies.gateway.envoyproxy.io
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: envoy-gateway
+  namespace: envoy-gateway-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: envoy-gateway
+        image: docker.io/envoyproxy/gateway:v1.4.1
+`
+)
+
+func Test_build(t *testing.T) {
+	manifesttest.WithFS(t, manifests, fstest.MapFS{
+		"manifests/gateway-api/standard-install.yaml":     {Data: []byte(gatewayClassesCRD)},
+		"manifests/gateway-api/experimental-install.yaml": {Data: []byte(gatewayClassesCRD + "---\n" + tcpRoutesCRD)},
+		"manifests/envoy-gateway/install.yaml":            {Data: []byte(implementation)},
+	})
+
+	testCases := []struct {
+		desc          string
+		channel       v1alpha1.GatewayAPIChannel
+		images        []types.Image
+		expectedNames []string
+		expectedImage string
+	}{
+		{
+			desc:    "should use standard channel by default",
+			channel: "",
+			expectedNames: []string{
+				"CustomResourceDefinition/gatewayclasses.gateway.networking.k8s.io",
+				"CustomResourceDefinition/envoyproxies.gateway.envoyproxy.io",
+				"Deployment/envoy-gateway-system/envoy-gateway",
+			},
+			expectedImage: "docker.io/envoyproxy/gateway:v1.4.1",
+		},
+		{
+			desc:    "should use experimental channel and override images",
+			channel: v1alpha1.GatewayAPIChannelExperimental,
+			images:  []types.Image{{Name: "docker.io/envoyproxy/gateway", NewName: "registry.local/envoyproxy/gateway"}},
+			expectedNames: []string{
+				"CustomResourceDefinition/gatewayclasses.gateway.networking.k8s.io",
+				"CustomResourceDefinition/tcproutes.gateway.networking.k8s.io",
+				"CustomResourceDefinition/envoyproxies.gateway.envoyproxy.io",
+				"Deployment/envoy-gateway-system/envoy-gateway",
+			},
+			expectedImage: "registry.local/envoyproxy/gateway:v1.4.1",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			objs, err := build(tC.channel, tC.images)
+			assert.NoError(t, err)
+
+			names := []string{}
+			for _, obj := range objs {
+				names = append(names, manifest.ObjectName(obj))
+				assert.Equal(t, labelPartOfValue, obj.GetLabels()[manifest.LabelPartOf])
+
+				containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+				for _, c := range containers {
+					assert.Equal(t, tC.expectedImage, c.(map[string]any)["image"])
+				}
+			}
+			assert.Equal(t, tC.expectedNames, names)
+		})
+	}
+}
+
+// Test_build_embedded guards against building the provider without the generated manifests.
+func Test_build_embedded(t *testing.T) {
+	for _, channel := range []v1alpha1.GatewayAPIChannel{v1alpha1.GatewayAPIChannelStandard, v1alpha1.GatewayAPIChannelExperimental} {
+		t.Run(string(channel), func(t *testing.T) {
+			objs, err := build(channel, nil)
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, obj := range objs {
+				names = append(names, manifest.ObjectName(obj))
+			}
+			assert.Contains(t, names, "CustomResourceDefinition/gatewayclasses.gateway.networking.k8s.io")
+			assert.Contains(t, names, "Deployment/envoy-gateway-system/envoy-gateway")
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	cluster := &clustersv1alpha1.Cluster{}
+	ac := addon.Context{
+		Cluster:  cluster,
+		Client:   c,
+		Config:   &v1alpha1.ProviderConfig{},
+		Recorder: events.NewFakeRecorder(10),
+	}
+
+	err := NewAddon().Configure(ctx, ac)
+	assert.ErrorIs(t, err, addon.ErrPending)
+	assert.True(t, meta.IsStatusConditionFalse(cluster.Status.Conditions, ConditionGatewayClassReady))
+
+	gc := newGatewayClass()
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(gc), gc))
+	assert.NoError(t, unstructured.SetNestedSlice(gc.Object, []any{map[string]any{"type": "Accepted", "status": "True"}}, "status", "conditions"))
+	assert.NoError(t, c.Update(ctx, gc))
+
+	assert.NoError(t, NewAddon().Configure(ctx, ac))
+	assert.True(t, meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionGatewayClassReady))
+}
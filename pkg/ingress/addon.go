@@ -0,0 +1,164 @@
+package ingress
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+)
+
+const (
+	// AddonName is the name of the ingress addon.
+	AddonName = "Ingress"
+
+	// EndpointHTTP is the name of the Cluster endpoint for HTTP traffic to the ingress controller.
+	EndpointHTTP = "ingress-http"
+	// EndpointHTTPS is the name of the Cluster endpoint for HTTPS traffic to the ingress controller.
+	EndpointHTTPS = "ingress-https"
+
+	// ConditionHostPortsPublished reports whether the HTTP and HTTPS ports of the ingress controller are published on
+	// the host. They can only be published when the kind cluster is created.
+	ConditionHostPortsPublished = "IngressHostPortsPublished"
+
+	// nodeLabelIngressReady selects the node the ingress controller runs on.
+	nodeLabelIngressReady = "ingress-ready"
+	// nodeLabelControlPlane is set on the control plane node by kubeadm.
+	nodeLabelControlPlane = "node-role.kubernetes.io/control-plane"
+)
+
+// NewAddon returns the addon that installs the ingress-nginx controller on the control plane node.
+// The HTTP and HTTPS ports of the node are published on the host when the kind cluster is created.
+func NewAddon() addon.Addon {
+	return &ingressAddon{}
+}
+
+var _ addon.Addon = &ingressAddon{}
+var _ addon.ClusterConfigurer = &ingressAddon{}
+
+type ingressAddon struct{}
+
+// Name implements addon.Addon.
+func (a *ingressAddon) Name() string {
+	return AddonName
+}
+
+// ConfigureCluster implements addon.ClusterConfigurer.
+func (a *ingressAddon) ConfigureCluster(cfg *kind.ClusterConfig) {
+	cfg.PortMappings = append(cfg.PortMappings,
+		kind.PortMapping{Name: EndpointHTTP, Scheme: "http", ContainerPort: 80},
+		kind.PortMapping{Name: EndpointHTTPS, Scheme: "https", ContainerPort: 443},
+	)
+	if cfg.NodeLabels == nil {
+		cfg.NodeLabels = map[string]string{}
+	}
+	cfg.NodeLabels[nodeLabelIngressReady] = "true"
+}
+
+// Install implements addon.Addon.
+func (a *ingressAddon) Install(ctx context.Context, ac addon.Context) error {
+	if err := labelControlPlaneNode(ctx, ac.Client); err != nil {
+		return err
+	}
+
+	objs, err := build(manifest.Images(config(ac.Config).Images))
+	if err != nil {
+		return err
+	}
+
+	result := manifest.Result{}
+	err = manifest.Apply(ctx, ac.Client, objs, &result)
+	if err == nil {
+		err = manifest.Prune(ctx, ac.Client, objs, manifest.Kinds(objs), pruneLabels, &result)
+	}
+	if result.HasChanges() {
+		ac.Recorder.Eventf(ac.Cluster, nil, corev1.EventTypeNormal, "IngressApplied", "ApplyIngress", "Applied ingress-nginx: %s", result)
+	}
+	return err
+}
+
+// Ready implements addon.Addon.
+func (a *ingressAddon) Ready(ctx context.Context, ac addon.Context) (bool, error) {
+	objs, err := build(manifest.Images(config(ac.Config).Images))
+	if err != nil {
+		return false, err
+	}
+
+	notReady, err := manifest.NotReady(ctx, ac.Client, objs)
+	return len(notReady) == 0, err
+}
+
+// Configure implements addon.Addon.
+// The host ports are published when the kind cluster is created and reported as endpoints by the Cluster controller.
+// Clusters that have been created before the addon was enabled get the ingress controller without host ports,
+// which is reported as condition instead of blocking the following addons.
+func (a *ingressAddon) Configure(_ context.Context, ac addon.Context) error {
+	hostPorts, err := kind.HostPortsFromCluster(ac.Cluster)
+	if err != nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:    ConditionHostPortsPublished,
+		Status:  metav1.ConditionFalse,
+		Reason:  "ClusterCreatedWithoutIngress",
+		Message: "The kind cluster has been created before the Ingress addon was enabled, recreate the Cluster to publish the HTTP and HTTPS ports on the host",
+	}
+	if slices.ContainsFunc(hostPorts, func(m kind.PortMapping) bool { return m.Name == EndpointHTTP }) {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "HostPortsPublished"
+		condition.Message = ""
+	}
+	meta.SetStatusCondition(&ac.Cluster.Status.Conditions, condition)
+	return nil
+}
+
+// Uninstall implements addon.Addon.
+func (a *ingressAddon) Uninstall(ctx context.Context, ac addon.Context) error {
+	objs, err := build(nil)
+	if err != nil {
+		return err
+	}
+
+	if err := manifest.Prune(ctx, ac.Client, nil, manifest.Kinds(objs), pruneLabels, &manifest.Result{}); err != nil {
+		return err
+	}
+
+	meta.RemoveStatusCondition(&ac.Cluster.Status.Conditions, ConditionHostPortsPublished)
+	return nil
+}
+
+// labelControlPlaneNode sets the label the ingress controller is scheduled by on the control plane node.
+// It is set when the kind cluster is created, but missing in clusters that have been created before the addon was enabled.
+func labelControlPlaneNode(ctx context.Context, c client.Client) error {
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes, client.HasLabels{nodeLabelControlPlane}); err != nil {
+		return err
+	}
+
+	for _, node := range nodes.Items {
+		if _, ok := node.Labels[nodeLabelIngressReady]; ok {
+			return nil
+		}
+	}
+	if len(nodes.Items) == 0 {
+		return nil
+	}
+
+	node := &nodes.Items[0]
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Labels[nodeLabelIngressReady] = "true"
+	return c.Patch(ctx, node, patch)
+}
+
+func config(pc *v1alpha1.ProviderConfig) v1alpha1.IngressConfig {
+	return ptr.Deref(pc.Spec.Ingress, v1alpha1.IngressConfig{})
+}
@@ -0,0 +1,70 @@
+package ingress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+func Test_labelControlPlaneNode(t *testing.T) {
+	ctx := context.Background()
+	controlPlane := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "test-control-plane",
+		Labels: map[string]string{nodeLabelControlPlane: ""},
+	}}
+	worker := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-worker"}}
+	c := fake.NewClientBuilder().WithObjects(controlPlane, worker).Build()
+
+	require.NoError(t, labelControlPlaneNode(ctx, c))
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(controlPlane), controlPlane))
+	assert.Equal(t, "true", controlPlane.Labels[nodeLabelIngressReady])
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(worker), worker))
+	assert.NotContains(t, worker.Labels, nodeLabelIngressReady)
+}
+
+func TestConfigure(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		hostPorts      []kind.PortMapping
+		expectedStatus metav1.ConditionStatus
+	}{
+		{
+			desc:           "should report published host ports",
+			hostPorts:      []kind.PortMapping{{Name: EndpointHTTP, Scheme: "http", ContainerPort: 80, HostPort: 30000}},
+			expectedStatus: metav1.ConditionTrue,
+		},
+		{
+			desc:           "should report a cluster created without the addon",
+			expectedStatus: metav1.ConditionFalse,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cluster := &clustersv1alpha1.Cluster{}
+			if tC.hostPorts != nil {
+				value, err := kind.EncodeHostPorts(tC.hostPorts)
+				require.NoError(t, err)
+				cluster.Annotations = map[string]string{kind.AnnotationHostPorts: value}
+			}
+
+			err := NewAddon().Configure(context.Background(), addon.Context{Cluster: cluster})
+			assert.NoError(t, err)
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionHostPortsPublished)
+			require.NotNil(t, condition)
+			assert.Equal(t, tC.expectedStatus, condition.Status)
+		})
+	}
+}
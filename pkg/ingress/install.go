@@ -0,0 +1,109 @@
+package ingress
+
+import (
+	"embed"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+)
+
+//go:generate curl -L --create-dirs -o manifests/ingress-nginx.yaml https://raw.githubusercontent.com/kubernetes/ingress-nginx/controller-v1.12.1/deploy/static/provider/kind/deploy.yaml
+
+var (
+	//go:embed manifests
+	embedded embed.FS
+
+	manifests = &manifest.Source{FS: embedded, Dir: "manifests", Package: "./pkg/ingress/..."}
+
+	pruneLabels = client.MatchingLabels{manifest.LabelManagedBy: manifest.LabelManagedByValue, manifest.LabelPartOf: labelPartOfValue}
+)
+
+const (
+	namespace        = "ingress-nginx"
+	labelPartOfValue = "ingress-nginx"
+
+	resourceBaseYAML = "ingress-nginx.yaml"
+
+	controllerName = "ingress-nginx-controller"
+
+	// webhookName is the name of the admission webhook objects in the upstream manifest.
+	webhookName = "ingress-nginx-admission"
+	// webhookPort is the port the controller serves the admission webhook on.
+	webhookPort = 8443
+	// webhookArgPrefix is the prefix of the controller flags that configure the admission webhook.
+	webhookArgPrefix = "--validating-webhook"
+	// webhookVolume is the volume of the controller that contains the certificate of the admission webhook.
+	webhookVolume = "webhook-cert"
+)
+
+func build(images []types.Image) ([]*unstructured.Unstructured, error) {
+	objs, err := manifests.Render(resourceBaseYAML, manifest.RenderOptions{
+		PartOf: labelPartOfValue,
+		Images: images,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return removeAdmissionWebhook(objs), nil
+}
+
+// removeAdmissionWebhook removes the admission webhook of the upstream manifest, i.e. its objects, the jobs that create
+// its certificate, and the flags, port and volume of the controller that serve it. The webhook only validates Ingresses
+// before they are stored, which is not needed in test clusters, and its certificate jobs delay the readiness.
+func removeAdmissionWebhook(objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	objs = slices.DeleteFunc(objs, func(obj *unstructured.Unstructured) bool {
+		return obj.GetLabels()["app.kubernetes.io/component"] == "admission-webhook" ||
+			strings.HasPrefix(obj.GetName(), webhookName) || obj.GetName() == controllerName+"-admission"
+	})
+
+	for _, obj := range objs {
+		if obj.GetKind() != "Deployment" || obj.GetName() != controllerName {
+			continue
+		}
+
+		podSpec, _, _ := unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+		containers, _, _ := unstructured.NestedSlice(podSpec, "containers")
+		for _, c := range containers {
+			container := c.(map[string]any)
+			filterList(container, "args", func(arg any) bool {
+				s, _ := arg.(string)
+				return strings.HasPrefix(s, webhookArgPrefix)
+			})
+			filterList(container, "ports", func(port any) bool {
+				p, _ := port.(map[string]any)
+				return p["containerPort"] == int64(webhookPort)
+			})
+			filterList(container, "volumeMounts", func(mount any) bool {
+				m, _ := mount.(map[string]any)
+				return m["name"] == webhookVolume
+			})
+		}
+		podSpec["containers"] = containers
+		filterList(podSpec, "volumes", func(volume any) bool {
+			v, _ := volume.(map[string]any)
+			return v["name"] == webhookVolume
+		})
+		_ = unstructured.SetNestedMap(obj.Object, podSpec, "spec", "template", "spec")
+	}
+	return objs
+}
+
+// filterList removes the items of the list in the given field that match, and the field if no item is left.
+func filterList(obj map[string]any, field string, remove func(any) bool) {
+	items, ok := obj[field].([]any)
+	if !ok {
+		return
+	}
+	items = slices.DeleteFunc(items, remove)
+	if len(items) == 0 {
+		delete(obj, field)
+		return
+	}
+	obj[field] = items
+}
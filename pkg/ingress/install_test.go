@@ -0,0 +1,94 @@
+package ingress
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest/manifesttest"
+)
+
+const ingressManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ingress-nginx-controller
+  namespace: ingress-nginx
+spec:
+  template:
+    spec:
+      containers:
+      - name: controller
+        image: registry.k8s.io/ingress-nginx/controller:v1.12.1
+        args:
+        - /nginx-ingress-controller
+        - --validating-webhook=:8443
+        - --validating-webhook-certificate=/usr/local/certificates/cert
+        - --validating-webhook-key=/usr/local/certificates/key
+        ports:
+        - containerPort: 80
+          name: http
+        - containerPort: 8443
+          name: webhook
+        volumeMounts:
+        - mountPath: /usr/local/certificates/
+          name: webhook-cert
+      volumes:
+      - name: webhook-cert
+        secret:
+          secretName: ingress-nginx-admission
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: ingress-nginx-controller-admission
+  namespace: ingress-nginx
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: ingress-nginx-admission-create
+  namespace: ingress-nginx
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: ingress-nginx-admission
+  labels:
+    app.kubernetes.io/component: admission-webhook
+`
+
+func Test_build(t *testing.T) {
+	objs, err := build([]types.Image{{Name: "registry.k8s.io/ingress-nginx/controller", NewName: "registry.local/ingress-nginx/controller"}})
+	assert.NoError(t, err)
+
+	images := []string{}
+	for _, obj := range objs {
+		assert.Equal(t, labelPartOfValue, obj.GetLabels()[manifest.LabelPartOf])
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		for _, c := range containers {
+			images = append(images, c.(map[string]any)["image"].(string))
+		}
+	}
+	assert.Equal(t, []string{"registry.local/ingress-nginx/controller:v1.12.1"}, images)
+}
+
+func Test_build_removeAdmissionWebhook(t *testing.T) {
+	manifesttest.WithFS(t, manifests, fstest.MapFS{"manifests/ingress-nginx.yaml": {Data: []byte(ingressManifest)}})
+
+	objs, err := build(nil)
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+
+	podSpec, _, _ := unstructured.NestedMap(objs[0].Object, "spec", "template", "spec")
+	assert.NotContains(t, podSpec, "volumes")
+	containers, _, _ := unstructured.NestedSlice(podSpec, "containers")
+	container := containers[0].(map[string]any)
+	assert.Equal(t, []any{"/nginx-ingress-controller"}, container["args"])
+	assert.Equal(t, []any{map[string]any{"containerPort": int64(80), "name": "http"}}, container["ports"])
+	assert.NotContains(t, container, "volumeMounts")
+}
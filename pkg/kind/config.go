@@ -0,0 +1,123 @@
+package kind
+
+import (
+	"maps"
+	"os"
+	"slices"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/yaml"
+)
+
+// ClusterConfig describes settings that are applied on top of the kind configuration file when a cluster is created.
+type ClusterConfig struct {
+	// PortMappings are ports of the control plane node that are published on the host.
+	PortMappings []PortMapping
+	// NodeLabels are set on the control plane node.
+	NodeLabels map[string]string
+	// ContainerdConfigPatches are applied to the containerd configuration of all nodes.
+	ContainerdConfigPatches []string
+	// RegistryMirrors are configured in containerd of all nodes.
+	RegistryMirrors []RegistryMirror
+	// Proxy is passed to all nodes as environment.
+	Proxy *ProxyConfig
+	// CACertificates are PEM encoded certificates that are trusted by all nodes in addition to the system roots.
+	CACertificates []string
+	// DisableDefaultCNI prevents kind from installing kindnet, so that another network plugin can be installed.
+	// kind does not wait for the nodes to become ready in this case.
+	DisableDefaultCNI bool
+	// PodSubnet is the CIDR of the pod IPs. Defaults to the kind default.
+	PodSubnet string
+	// ServiceSubnet is the CIDR of the service IPs. Defaults to the kind default.
+	ServiceSubnet string
+	// Network is the Docker network the nodes are created in. Defaults to DefaultNetworkName.
+	Network string
+	// NodeImage is the node image of all nodes. Defaults to the images of the kind configuration file.
+	NodeImage string
+}
+
+// PortMapping publishes a port of the control plane node on the host.
+type PortMapping struct {
+	// Name identifies the mapping. It is used as name of the endpoint in the Cluster status.
+	Name string `json:"name"`
+	// Scheme is the URL scheme of the endpoint in the Cluster status.
+	Scheme string `json:"scheme"`
+	// ContainerPort is the port of the node container.
+	ContainerPort int32 `json:"containerPort"`
+	// HostPort is the port on the host. It is assigned by AssignHostPorts.
+	HostPort int32 `json:"hostPort,omitempty"`
+}
+
+// loadKindConfig reads the kind configuration file. If no file is given, an empty configuration is returned.
+func loadKindConfig(configFile string) (*v1alpha4.Cluster, error) {
+	cfg := &v1alpha4.Cluster{}
+	if configFile != "" {
+		data, err := os.ReadFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.Kind = "Cluster"
+	cfg.APIVersion = "kind.x-k8s.io/v1alpha4"
+	return cfg, nil
+}
+
+// applyClusterConfig merges the given settings into the kind configuration.
+func applyClusterConfig(kindCfg *v1alpha4.Cluster, cfg ClusterConfig) {
+	kindCfg.ContainerdConfigPatches = append(kindCfg.ContainerdConfigPatches, cfg.ContainerdConfigPatches...)
+	if len(cfg.RegistryMirrors) > 0 && !slices.Contains(kindCfg.ContainerdConfigPatches, RegistryContainerdConfigPatch) {
+		kindCfg.ContainerdConfigPatches = append(kindCfg.ContainerdConfigPatches, RegistryContainerdConfigPatch)
+	}
+
+	if cfg.DisableDefaultCNI {
+		kindCfg.Networking.DisableDefaultCNI = true
+	}
+	if cfg.PodSubnet != "" {
+		kindCfg.Networking.PodSubnet = cfg.PodSubnet
+	}
+	if cfg.ServiceSubnet != "" {
+		kindCfg.Networking.ServiceSubnet = cfg.ServiceSubnet
+	}
+
+	if cfg.NodeImage != "" {
+		controlPlaneNode(kindCfg)
+		for i := range kindCfg.Nodes {
+			kindCfg.Nodes[i].Image = cfg.NodeImage
+		}
+	}
+
+	if len(cfg.PortMappings) == 0 && len(cfg.NodeLabels) == 0 {
+		return
+	}
+
+	node := controlPlaneNode(kindCfg)
+	for _, m := range cfg.PortMappings {
+		node.ExtraPortMappings = append(node.ExtraPortMappings, v1alpha4.PortMapping{
+			ContainerPort: m.ContainerPort,
+			HostPort:      m.HostPort,
+			Protocol:      v1alpha4.PortMappingProtocolTCP,
+		})
+	}
+	if len(cfg.NodeLabels) > 0 {
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		maps.Copy(node.Labels, cfg.NodeLabels)
+	}
+}
+
+// controlPlaneNode returns the first control plane node of the kind configuration, adding one if there are no nodes.
+func controlPlaneNode(kindCfg *v1alpha4.Cluster) *v1alpha4.Node {
+	for i, node := range kindCfg.Nodes {
+		if node.Role == "" || node.Role == v1alpha4.ControlPlaneRole {
+			return &kindCfg.Nodes[i]
+		}
+	}
+
+	kindCfg.Nodes = append([]v1alpha4.Node{{Role: v1alpha4.ControlPlaneRole}}, kindCfg.Nodes...)
+	return &kindCfg.Nodes[0]
+}
@@ -0,0 +1,106 @@
+package kind
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+func Test_loadKindConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "kind.yaml")
+	err := os.WriteFile(configFile, []byte(`kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+- role: control-plane
+- role: worker
+`), 0o600)
+	assert.NoError(t, err)
+
+	cfg, err := loadKindConfig(configFile)
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Nodes, 2)
+	assert.Equal(t, v1alpha4.WorkerRole, cfg.Nodes[1].Role)
+
+	cfg, err = loadKindConfig("")
+	assert.NoError(t, err)
+	assert.Equal(t, "Cluster", cfg.Kind)
+	assert.Empty(t, cfg.Nodes)
+}
+
+func Test_applyClusterConfig(t *testing.T) {
+	clusterCfg := ClusterConfig{
+		PortMappings:            []PortMapping{{Name: "http", Scheme: "http", ContainerPort: 80, HostPort: 20000}},
+		NodeLabels:              map[string]string{"ingress-ready": "true"},
+		ContainerdConfigPatches: []string{RegistryContainerdConfigPatch},
+	}
+
+	testCases := []struct {
+		desc         string
+		kindCfg      *v1alpha4.Cluster
+		expectedNode int
+		expectedLen  int
+	}{
+		{
+			desc:         "should add control plane node if there are no nodes",
+			kindCfg:      &v1alpha4.Cluster{},
+			expectedNode: 0,
+			expectedLen:  1,
+		},
+		{
+			desc: "should use first control plane node",
+			kindCfg: &v1alpha4.Cluster{Nodes: []v1alpha4.Node{
+				{Role: v1alpha4.WorkerRole},
+				{Role: v1alpha4.ControlPlaneRole},
+			}},
+			expectedNode: 1,
+			expectedLen:  2,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			applyClusterConfig(tC.kindCfg, clusterCfg)
+			assert.Len(t, tC.kindCfg.Nodes, tC.expectedLen)
+			assert.Equal(t, []string{RegistryContainerdConfigPatch}, tC.kindCfg.ContainerdConfigPatches)
+
+			node := tC.kindCfg.Nodes[tC.expectedNode]
+			assert.Equal(t, []v1alpha4.PortMapping{{ContainerPort: 80, HostPort: 20000, Protocol: v1alpha4.PortMappingProtocolTCP}}, node.ExtraPortMappings)
+			assert.Equal(t, "true", node.Labels["ingress-ready"])
+		})
+	}
+}
+
+func Test_applyClusterConfig_registryMirrors(t *testing.T) {
+	kindCfg := &v1alpha4.Cluster{}
+	applyClusterConfig(kindCfg, ClusterConfig{
+		ContainerdConfigPatches: []string{RegistryContainerdConfigPatch},
+		RegistryMirrors:         []RegistryMirror{{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}}},
+	})
+	assert.Equal(t, []string{RegistryContainerdConfigPatch}, kindCfg.ContainerdConfigPatches)
+	assert.Empty(t, kindCfg.Nodes)
+}
+
+func Test_applyClusterConfig_networking(t *testing.T) {
+	kindCfg := &v1alpha4.Cluster{}
+	applyClusterConfig(kindCfg, ClusterConfig{
+		DisableDefaultCNI: true,
+		PodSubnet:         "10.100.0.0/16",
+		ServiceSubnet:     "10.101.0.0/16",
+	})
+	assert.True(t, kindCfg.Networking.DisableDefaultCNI)
+	assert.Equal(t, "10.100.0.0/16", kindCfg.Networking.PodSubnet)
+	assert.Equal(t, "10.101.0.0/16", kindCfg.Networking.ServiceSubnet)
+	assert.Empty(t, kindCfg.Nodes)
+}
+
+func Test_applyClusterConfig_nodeImage(t *testing.T) {
+	kindCfg := &v1alpha4.Cluster{Nodes: []v1alpha4.Node{{Role: v1alpha4.WorkerRole, Image: "kindest/node:v1.32.5"}}}
+	applyClusterConfig(kindCfg, ClusterConfig{NodeImage: "kindest/node:v1.33.1"})
+	require.Len(t, kindCfg.Nodes, 2)
+	for _, node := range kindCfg.Nodes {
+		assert.Equal(t, "kindest/node:v1.33.1", node.Image)
+	}
+}
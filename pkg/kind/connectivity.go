@@ -0,0 +1,172 @@
+package kind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+)
+
+const (
+	// clusterSubnetsMax is the number of clusters pod and service subnets can be allocated for.
+	clusterSubnetsMax = 32
+)
+
+var (
+	// AnnotationPodSubnet is the annotation used to store the pod subnet a cluster has been created with.
+	AnnotationPodSubnet = v1alpha1.SchemeGroupVersion.Group + "/pod-subnet"
+	// AnnotationServiceSubnet is the annotation used to store the service subnet a cluster has been created with.
+	AnnotationServiceSubnet = v1alpha1.SchemeGroupVersion.Group + "/service-subnet"
+
+	// ClusterSubnetRange contains the pod and service subnets that are allocated for cross-cluster connectivity.
+	// The pod subnets are allocated from the lower half, the service subnets from the upper half.
+	ClusterSubnetRange = net.IPNet{IP: net.IPv4(100, 64, 0, 0).To4(), Mask: net.CIDRMask(10, 32)}
+
+	errNoClusterSubnetsAvailable = errors.New("no pod and service subnets available")
+)
+
+// Route routes the destination subnet via the gateway.
+type Route struct {
+	Destination net.IPNet
+	Gateway     net.IP
+}
+
+// NextAvailableClusterSubnets finds the next pod and service subnets that do not overlap with the ones of other clusters.
+func NextAvailableClusterSubnets(ctx context.Context, c client.Client) (net.IPNet, net.IPNet, error) {
+	lockListClusters.Lock()
+	defer lockListClusters.Unlock()
+
+	clusters := &clustersv1alpha1.ClusterList{}
+	if err := c.List(ctx, clusters); err != nil {
+		return net.IPNet{}, net.IPNet{}, err
+	}
+
+	taken := map[string]bool{}
+	for _, cluster := range clusters.Items {
+		podSubnet, _, err := ClusterSubnetsFromCluster(&cluster)
+		if err != nil {
+			return net.IPNet{}, net.IPNet{}, err
+		}
+		if podSubnet != nil {
+			taken[podSubnet.String()] = true
+		}
+	}
+
+	for i := range clusterSubnetsMax {
+		podSubnet, serviceSubnet := clusterSubnets(i)
+		if !taken[podSubnet.String()] {
+			return podSubnet, serviceSubnet, nil
+		}
+	}
+	return net.IPNet{}, net.IPNet{}, errNoClusterSubnetsAvailable
+}
+
+// clusterSubnets returns the /16 pod and service subnets with the given index in ClusterSubnetRange.
+func clusterSubnets(i int) (net.IPNet, net.IPNet) {
+	base := ClusterSubnetRange.IP.To4()
+	podIP := net.IPv4(base[0], base[1]+byte(i), 0, 0).To4()
+	serviceIP := net.IPv4(base[0], base[1]+clusterSubnetsMax+byte(i), 0, 0).To4()
+	return net.IPNet{IP: podIP, Mask: net.CIDRMask(16, 32)}, net.IPNet{IP: serviceIP, Mask: net.CIDRMask(16, 32)}
+}
+
+// ClusterSubnetsFromCluster extracts the pod and service subnets from the cluster annotations.
+// The service subnet is only set for clusters with cross-cluster connectivity.
+func ClusterSubnetsFromCluster(c *clustersv1alpha1.Cluster) (*net.IPNet, *net.IPNet, error) {
+	var podSubnet, serviceSubnet *net.IPNet
+	if s, ok := c.Annotations[AnnotationPodSubnet]; ok {
+		_, parsed, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		podSubnet = parsed
+	}
+	if s, ok := c.Annotations[AnnotationServiceSubnet]; ok {
+		_, parsed, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		serviceSubnet = parsed
+	}
+	return podSubnet, serviceSubnet, nil
+}
+
+// NodeIPs returns the IP addresses of the node containers of the given kind cluster.
+func NodeIPs(ctx context.Context, clusterName string) ([]net.IP, error) {
+	nodes, err := clusterNodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := make([]net.IP, 0, len(nodes))
+	for _, node := range nodes {
+		ip, err := getDockerContainerIP(ctx, node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get IP of node %s: %w", node, err)
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// EnsureRoutes makes sure the given routes exist on all nodes of the given kind cluster.
+// Routes to other subnets of ClusterSubnetRange that have been added before are removed.
+func EnsureRoutes(ctx context.Context, clusterName string, routes []Route) error {
+	nodes, err := clusterNodes(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
+	desired := map[string]bool{}
+	for _, r := range routes {
+		desired[r.Destination.String()] = true
+	}
+
+	for _, node := range nodes {
+		out, err := docker(ctx, "exec", node, "ip", "-4", "-o", "route", "show").Output()
+		if err != nil {
+			return fmt.Errorf("failed to list routes of node %s: %w", node, err)
+		}
+		for _, stale := range staleRoutes(string(out), desired) {
+			if err := execNode(ctx, node, "ip route del "+stale); err != nil {
+				return fmt.Errorf("failed to remove route to %s on node %s: %w", stale, node, err)
+			}
+		}
+
+		for _, r := range routes {
+			if err := execNode(ctx, node, fmt.Sprintf("ip route replace %s via %s", r.Destination.String(), r.Gateway.String())); err != nil {
+				return fmt.Errorf("failed to add route to %s on node %s: %w", r.Destination.String(), node, err)
+			}
+		}
+	}
+	return nil
+}
+
+// staleRoutes returns the destinations of /16 routes in ClusterSubnetRange from the output of `ip -o route show`
+// that are not desired. More specific routes, like the ones of the network plugin to the pod subnets of the nodes,
+// are left alone.
+func staleRoutes(out string, desired map[string]bool) []string {
+	stale := []string{}
+	for line := range strings.Lines(out) {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[1] != "via" {
+			continue
+		}
+		_, dst, err := net.ParseCIDR(fields[0])
+		if err != nil {
+			continue
+		}
+		if ones, _ := dst.Mask.Size(); ones != 16 || !ClusterSubnetRange.Contains(dst.IP) {
+			continue
+		}
+		if !desired[dst.String()] {
+			stale = append(stale, dst.String())
+		}
+	}
+	return stale
+}
@@ -0,0 +1,57 @@
+package kind
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+)
+
+func Test_clusterSubnets(t *testing.T) {
+	podSubnet, serviceSubnet := clusterSubnets(0)
+	assert.Equal(t, "100.64.0.0/16", podSubnet.String())
+	assert.Equal(t, "100.96.0.0/16", serviceSubnet.String())
+
+	podSubnet, serviceSubnet = clusterSubnets(clusterSubnetsMax - 1)
+	assert.Equal(t, "100.95.0.0/16", podSubnet.String())
+	assert.Equal(t, "100.127.0.0/16", serviceSubnet.String())
+	assert.True(t, ClusterSubnetRange.Contains(serviceSubnet.IP))
+}
+
+func TestNextAvailableClusterSubnets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clustersv1alpha1.AddToScheme(scheme)
+
+	newCluster := func(name string, annotations map[string]string) *clustersv1alpha1.Cluster {
+		return &clustersv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations}}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newCluster("a", map[string]string{AnnotationPodSubnet: "100.64.0.0/16", AnnotationServiceSubnet: "100.96.0.0/16"}),
+		newCluster("b", map[string]string{AnnotationPodSubnet: "10.244.0.0/16"}),
+		newCluster("c", nil),
+	).Build()
+
+	podSubnet, serviceSubnet, err := NextAvailableClusterSubnets(context.Background(), c)
+	assert.NoError(t, err)
+	assert.Equal(t, "100.65.0.0/16", podSubnet.String())
+	assert.Equal(t, "100.97.0.0/16", serviceSubnet.String())
+}
+
+func Test_staleRoutes(t *testing.T) {
+	out := `default via 172.18.0.1 dev eth0
+100.64.1.0/24 via 172.18.0.3 dev eth0
+100.65.0.0/16 via 172.18.0.5 dev eth0
+100.97.0.0/16 via 172.18.0.5 dev eth0
+100.66.0.0/16 via 172.18.0.6 dev eth0
+blackhole 100.64.0.0/26 proto bird
+172.18.0.0/16 dev eth0 proto kernel scope link src 172.18.0.2
+`
+	desired := map[string]bool{"100.65.0.0/16": true, "100.97.0.0/16": true}
+	assert.Equal(t, []string{"100.66.0.0/16"}, staleRoutes(out, desired))
+}
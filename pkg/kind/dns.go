@@ -0,0 +1,298 @@
+package kind
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultDNSImage is the CoreDNS image used for the DNS server container.
+	DefaultDNSImage = "coredns/coredns:1.12.1"
+	// DefaultDNSHostPort is the port on the host the DNS server is published on.
+	DefaultDNSHostPort = 1053
+	// DefaultDNSDomain is the domain the DNS server is authoritative for.
+	DefaultDNSDomain = "kind.local"
+	// DNSName is the name of the DNS server container.
+	DNSName = "kind-dns"
+
+	dnsConfigDir      = "/etc/coredns"
+	dnsHostsFile      = "kind.hosts"
+	labelDNSConfigSum = "kind.clusters.openmcp.cloud/dns-config-hash"
+
+	// dnsSectionPrefix starts the section of a kind cluster in the hosts file.
+	dnsSectionPrefix = "# kind-cluster: "
+)
+
+var (
+	// lockDNS serializes the updates of the hosts file, which contains the records of all kind clusters.
+	lockDNS = sync.Mutex{}
+)
+
+// DNSConfig configures the DNS server container that serves the records of all kind clusters.
+type DNSConfig struct {
+	// Image is the CoreDNS image. Defaults to DefaultDNSImage.
+	Image string
+	// HostPort is the port on the host the DNS server is published on. Defaults to DefaultDNSHostPort.
+	HostPort int32
+	// Domain is the domain the DNS server is authoritative for. Defaults to DefaultDNSDomain.
+	Domain string
+}
+
+// Address returns the address under which the DNS server on the Docker endpoint of the context is reachable.
+func (cfg DNSConfig) Address(ctx context.Context) string {
+	return net.JoinHostPort(dockerEndpointFrom(ctx).PublishHost(), strconv.Itoa(int(cfg.hostPort())))
+}
+
+// ClusterDomain returns the domain of the records of the given cluster. It contains the namespace of the cluster, so
+// that clusters with the same name in different namespaces do not share their records.
+func (cfg DNSConfig) ClusterDomain(cluster, namespace string) string {
+	return cluster + "." + namespace + "." + cfg.domain()
+}
+
+func (cfg DNSConfig) image() string {
+	if cfg.Image == "" {
+		return DefaultDNSImage
+	}
+	return cfg.Image
+}
+
+func (cfg DNSConfig) hostPort() int32 {
+	if cfg.HostPort == 0 {
+		return DefaultDNSHostPort
+	}
+	return cfg.HostPort
+}
+
+func (cfg DNSConfig) domain() string {
+	if cfg.Domain == "" {
+		return DefaultDNSDomain
+	}
+	return cfg.Domain
+}
+
+// DNSRecord maps a host name to an IP address.
+type DNSRecord struct {
+	Name string
+	IP   net.IP
+}
+
+// EnsureDNS makes sure the DNS server container is running with the given configuration. It only serves the host, so it
+// runs in the default bridge network of Docker, which exists on every Docker host, unlike the networks of kind.
+// If the container exists with a different configuration, it is recreated and the records are kept.
+func EnsureDNS(ctx context.Context, cfg DNSConfig) error {
+	corefile := renderCorefile(cfg)
+	configSum := sha256.Sum256([]byte(cfg.image() + cfg.Address(ctx) + corefile))
+	configHash := hex.EncodeToString(configSum[:])[:16]
+
+	lockDNS.Lock()
+	defer lockDNS.Unlock()
+
+	currentHash, err := getDockerContainerLabel(ctx, DNSName, labelDNSConfigSum)
+	switch {
+	case errors.Is(err, errContainerNotFound):
+		return runDNS(ctx, cfg, corefile, configHash, "")
+	case err != nil:
+		return err
+	case currentHash != configHash:
+		hosts, err := readDNSHosts(ctx)
+		if err != nil {
+			return err
+		}
+		if err := DeleteDNS(ctx); err != nil {
+			return err
+		}
+		return runDNS(ctx, cfg, corefile, configHash, hosts)
+	}
+
+	cmd := docker(ctx, "container", "start", DNSName)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to start DNS container: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// DeleteDNS removes the DNS server container together with the records.
+func DeleteDNS(ctx context.Context) error {
+	cmd := docker(ctx, "container", "rm", "--force", DNSName)
+	if out, err := cmd.CombinedOutput(); err != nil && !isNoSuchContainer(out) {
+		return fmt.Errorf("failed to remove DNS container: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// SetDNSRecords replaces the records of the given kind cluster in the DNS server. The records of other clusters are kept.
+// CoreDNS picks up the change within a few seconds.
+func SetDNSRecords(ctx context.Context, clusterName string, records []DNSRecord) error {
+	lockDNS.Lock()
+	defer lockDNS.Unlock()
+
+	current, err := readDNSHosts(ctx)
+	if err != nil {
+		return err
+	}
+	updated := replaceHostsSection(current, clusterName, records)
+	if updated == current {
+		return nil
+	}
+	return writeDNSFiles(ctx, map[string]string{dnsHostsFile: updated})
+}
+
+// DeleteDNSRecords removes the records of the given kind cluster from the DNS server.
+// It does not fail if the DNS server container does not exist.
+func DeleteDNSRecords(ctx context.Context, clusterName string) error {
+	if _, err := getDockerContainerLabel(ctx, DNSName, labelDNSConfigSum); err != nil {
+		if errors.Is(err, errContainerNotFound) {
+			return nil
+		}
+		return err
+	}
+	return SetDNSRecords(ctx, clusterName, nil)
+}
+
+// runDNS creates the DNS server container, copies the configuration and the given hosts file into it and starts it.
+func runDNS(ctx context.Context, cfg DNSConfig, corefile, configHash, hosts string) error {
+	listen := net.JoinHostPort(dockerEndpointFrom(ctx).listenAddress(), strconv.Itoa(int(cfg.hostPort())))
+	args := []string{
+		"create",
+		"--name", DNSName,
+		"--restart", "unless-stopped",
+		"--publish", listen + ":53/udp",
+		"--publish", listen + ":53/tcp",
+		"--label", labelManagedBy + "=" + labelManagedByValue,
+		"--label", labelDNSConfigSum + "=" + configHash,
+		cfg.image(),
+		"-conf", dnsConfigDir + "/Corefile",
+	}
+
+	cmd := docker(ctx, args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create DNS container: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	if err := writeDNSFiles(ctx, map[string]string{"Corefile": corefile, dnsHostsFile: hosts}); err != nil {
+		return err
+	}
+
+	cmd = docker(ctx, "container", "start", DNSName)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to start DNS container: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// readDNSHosts returns the hosts file of the DNS server container. The CoreDNS image has no shell, so the file is
+// copied out of the container as tar archive.
+func readDNSHosts(ctx context.Context) (string, error) {
+	cmd := docker(ctx, "container", "cp", DNSName+":"+dnsConfigDir+"/"+dnsHostsFile, "-")
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read DNS records: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	tr := tar.NewReader(bytes.NewReader(out))
+	if _, err := tr.Next(); err != nil {
+		return "", fmt.Errorf("failed to read DNS records: %w", err)
+	}
+	content, err := io.ReadAll(tr)
+	return string(content), err
+}
+
+// writeDNSFiles copies the given files into the configuration directory of the DNS server container.
+func writeDNSFiles(ctx context.Context, files map[string]string) error {
+	archive, err := tarFiles(files)
+	if err != nil {
+		return err
+	}
+
+	cmd := docker(ctx, "container", "cp", "-", DNSName+":"+dnsConfigDir)
+	cmd.Stdin = bytes.NewReader(archive)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write DNS configuration: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func tarFiles(files map[string]string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(files[name])),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write([]byte(files[name])); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderCorefile returns the CoreDNS configuration that answers queries for the domain from the hosts file.
+// Names that are not in the hosts file do not exist; other domains are not served.
+func renderCorefile(cfg DNSConfig) string {
+	return fmt.Sprintf(`%s:53 {
+    errors
+    hosts %s/%s {
+        reload 2s
+    }
+}
+`, cfg.domain(), dnsConfigDir, dnsHostsFile)
+}
+
+// replaceHostsSection replaces the records of the given kind cluster in the hosts file content.
+// Each cluster has its own section, which starts with a comment line containing the name of the kind cluster.
+// The section is removed if there are no records.
+func replaceHostsSection(content, clusterName string, records []DNSRecord) string {
+	out := &strings.Builder{}
+	inSection := false
+	for line := range strings.Lines(content) {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), dnsSectionPrefix); ok {
+			inSection = name == clusterName
+		}
+		if !inSection {
+			out.WriteString(line)
+		}
+	}
+
+	if len(records) == 0 {
+		return out.String()
+	}
+
+	records = slices.Clone(records)
+	slices.SortFunc(records, func(a, b DNSRecord) int {
+		return strings.Compare(a.Name+" "+a.IP.String(), b.Name+" "+b.IP.String())
+	})
+	out.WriteString(dnsSectionPrefix + clusterName + "\n")
+	for _, r := range records {
+		fmt.Fprintf(out, "%s %s\n", r.IP.String(), r.Name)
+	}
+	return out.String()
+}
@@ -0,0 +1,105 @@
+package kind
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSConfig(t *testing.T) {
+	ctx := context.Background()
+	cfg := DNSConfig{}
+	assert.Equal(t, "127.0.0.1:1053", cfg.Address(ctx))
+	assert.Equal(t, "test.default.kind.local", cfg.ClusterDomain("test", "default"))
+
+	cfg = DNSConfig{HostPort: 5300, Domain: "example.test"}
+	assert.Equal(t, "127.0.0.1:5300", cfg.Address(ctx))
+	assert.Equal(t, "test.default.example.test", cfg.ClusterDomain("test", "default"))
+
+	ctx = WithDockerEndpoint(ctx, DockerEndpoint{Host: "tcp://docker.example.com:2376"})
+	assert.Equal(t, "docker.example.com:5300", cfg.Address(ctx))
+}
+
+func Test_renderCorefile(t *testing.T) {
+	expected := `kind.local:53 {
+    errors
+    hosts /etc/coredns/kind.hosts {
+        reload 2s
+    }
+}
+`
+	assert.Equal(t, expected, renderCorefile(DNSConfig{}))
+}
+
+func Test_replaceHostsSection(t *testing.T) {
+	content := `# kind-cluster: a.1234
+172.18.255.1 web.default.a.kind.local
+# kind-cluster: b.5678
+172.18.254.1 web.default.b.kind.local
+`
+	testCases := []struct {
+		desc        string
+		clusterName string
+		records     []DNSRecord
+		expected    string
+	}{
+		{
+			desc:        "should replace section of cluster",
+			clusterName: "a.1234",
+			records: []DNSRecord{
+				{Name: "web.default.a.kind.local", IP: net.ParseIP("172.18.255.2")},
+				{Name: "api.default.a.kind.local", IP: net.ParseIP("172.18.255.3")},
+			},
+			expected: `# kind-cluster: b.5678
+172.18.254.1 web.default.b.kind.local
+# kind-cluster: a.1234
+172.18.255.3 api.default.a.kind.local
+172.18.255.2 web.default.a.kind.local
+`,
+		},
+		{
+			desc:        "should add section of new cluster",
+			clusterName: "c.9012",
+			records:     []DNSRecord{{Name: "web.default.c.kind.local", IP: net.ParseIP("172.18.253.1")}},
+			expected: content + `# kind-cluster: c.9012
+172.18.253.1 web.default.c.kind.local
+`,
+		},
+		{
+			desc:        "should remove section without records",
+			clusterName: "b.5678",
+			expected: `# kind-cluster: a.1234
+172.18.255.1 web.default.a.kind.local
+`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.expected, replaceHostsSection(content, tC.clusterName, tC.records))
+		})
+	}
+}
+
+func Test_tarFiles(t *testing.T) {
+	archive, err := tarFiles(map[string]string{"kind.hosts": "hosts", "Corefile": "config"})
+	assert.NoError(t, err)
+
+	tr := tar.NewReader(bytes.NewReader(archive))
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		assert.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+	assert.Equal(t, map[string]string{"kind.hosts": "hosts", "Corefile": "config"}, files)
+}
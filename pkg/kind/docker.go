@@ -0,0 +1,86 @@
+package kind
+
+import (
+	"context"
+	"os/exec"
+	"time"
+
+	"github.com/openmcp-project/cluster-provider-kind/pkg/metrics"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/tracing"
+)
+
+const (
+	// attrCluster is the span attribute for the name of the kind cluster.
+	attrCluster = "kind.cluster"
+	// attrNetwork is the span attribute for the Docker network.
+	attrNetwork = "kind.network"
+)
+
+// dockerCmd is a docker command whose duration and failure are recorded in the docker command metrics.
+// Each run is traced as a child span of the context the command has been created with.
+type dockerCmd struct {
+	*exec.Cmd
+	ctx     context.Context
+	command string
+}
+
+// docker returns the docker command with the given arguments for the Docker endpoint of the context.
+func docker(ctx context.Context, args ...string) *dockerCmd {
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Env = dockerEndpointFrom(ctx).cmdEnv()
+	return &dockerCmd{
+		Cmd:     cmd,
+		ctx:     ctx,
+		command: dockerCommandName(args),
+	}
+}
+
+// Run runs the command like exec.Cmd.Run.
+func (c *dockerCmd) Run() error {
+	done := c.start()
+	err := c.Cmd.Run()
+	done(err)
+	return err
+}
+
+// Output runs the command like exec.Cmd.Output.
+func (c *dockerCmd) Output() ([]byte, error) {
+	done := c.start()
+	out, err := c.Cmd.Output()
+	done(err)
+	return out, err
+}
+
+// CombinedOutput runs the command like exec.Cmd.CombinedOutput.
+func (c *dockerCmd) CombinedOutput() ([]byte, error) {
+	done := c.start()
+	out, err := c.Cmd.CombinedOutput()
+	done(err)
+	return out, err
+}
+
+// start starts the span of the command and returns the function that ends it and records the metrics.
+func (c *dockerCmd) start() func(error) {
+	start := time.Now()
+	// The arguments are not recorded, since they may contain credentials, e.g. of a proxy.
+	_, span := tracing.Start(c.ctx, "docker "+c.command)
+	return func(err error) {
+		metrics.ObserveDockerCommand(c.command, time.Since(start), err)
+		tracing.End(span, err)
+	}
+}
+
+// dockerCommandName returns the name of the docker command used as metric label, e.g. "container inspect" or "exec".
+// Management commands are reported together with their subcommand.
+func dockerCommandName(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	switch args[0] {
+	case "container", "image", "network", "volume":
+		if len(args) > 1 {
+			return args[0] + " " + args[1]
+		}
+	}
+	return args[0]
+}
@@ -0,0 +1,64 @@
+package kind
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_dockerCommandName(t *testing.T) {
+	testCases := []struct {
+		desc string
+		args []string
+		want string
+	}{
+		{
+			desc: "management command",
+			args: []string{"container", "inspect", "-f", "{{.Id}}", "kind-control-plane"},
+			want: "container inspect",
+		},
+		{
+			desc: "management command without subcommand",
+			args: []string{"network"},
+			want: "network",
+		},
+		{
+			desc: "command",
+			args: []string{"exec", "kind-control-plane", "ip", "route"},
+			want: "exec",
+		},
+		{
+			desc: "create",
+			args: []string{"create", "--name", DNSName, DefaultDNSImage},
+			want: "create",
+		},
+		{
+			desc: "no arguments",
+			want: "",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, dockerCommandName(tC.args))
+		})
+	}
+}
+
+func Test_dockerCmd_span(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	// The command is traced whether docker is available or not.
+	_, _ = docker(ctx, "version", "--format", "{{.Server.Version}}").Output()
+	parent.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "docker version", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
@@ -0,0 +1,126 @@
+package kind
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	envDockerHost      = "DOCKER_HOST"
+	envDockerTLSVerify = "DOCKER_TLS_VERIFY"
+	envDockerCertPath  = "DOCKER_CERT_PATH"
+
+	// DefaultDockerEndpointName is the name of the Docker daemon of the process in metrics.
+	DefaultDockerEndpointName = "default"
+
+	localhost = "127.0.0.1"
+)
+
+// defaultDockerEnv is the Docker configuration of the process, which is used for the default endpoint. It is captured
+// at startup, since the environment of the process is changed while kind is called for other endpoints.
+var defaultDockerEnv = map[string]string{
+	envDockerHost:      os.Getenv(envDockerHost),
+	envDockerTLSVerify: os.Getenv(envDockerTLSVerify),
+	envDockerCertPath:  os.Getenv(envDockerCertPath),
+}
+
+// processEnv is the environment of the process at startup, which docker commands are run with. The current environment
+// cannot be used, since it contains the proxy, network and Docker endpoint of concurrent kind operations, and docker
+// commands may run while such an operation holds lockEnv.
+var processEnv = os.Environ()
+
+// DockerEndpoint is a Docker daemon the kind clusters run on.
+type DockerEndpoint struct {
+	// Name identifies the Docker daemon in metrics. Defaults to DefaultDockerEndpointName.
+	Name string
+	// Host is the address of the Docker daemon like DOCKER_HOST, e.g. unix:///var/run/docker.sock or
+	// tcp://docker.example.com:2376. The Docker daemon of the process is used if it is empty.
+	Host string
+	// TLSCertPath is the directory of the client certificate and the CA like DOCKER_CERT_PATH.
+	// The certificate of the daemon is verified if it is set.
+	TLSCertPath string
+}
+
+type dockerEndpointKey struct{}
+
+// WithDockerEndpoint returns a context whose docker commands and kind operations are run against the endpoint.
+func WithDockerEndpoint(ctx context.Context, endpoint DockerEndpoint) context.Context {
+	return context.WithValue(ctx, dockerEndpointKey{}, endpoint)
+}
+
+// dockerEndpointFrom returns the endpoint of the context, the default endpoint if none is set.
+func dockerEndpointFrom(ctx context.Context) DockerEndpoint {
+	endpoint, _ := ctx.Value(dockerEndpointKey{}).(DockerEndpoint)
+	return endpoint
+}
+
+// name returns the name of the endpoint in metrics.
+func (e DockerEndpoint) name() string {
+	if e.Name == "" {
+		return DefaultDockerEndpointName
+	}
+	return e.Name
+}
+
+// PublishHost returns the host under which the ports published by containers of the endpoint are reachable, i.e. the
+// host of remote TCP and SSH endpoints and the loopback address for local ones.
+func (e DockerEndpoint) PublishHost() string {
+	if !e.remote() {
+		return localhost
+	}
+	u, err := url.Parse(e.Host)
+	if err != nil || u.Hostname() == "" {
+		return localhost
+	}
+	return u.Hostname()
+}
+
+// listenAddress returns the address ports are published on. Ports of remote endpoints are published on all interfaces,
+// since the loopback address of the remote host is not reachable.
+func (e DockerEndpoint) listenAddress() string {
+	if e.remote() {
+		return "0.0.0.0"
+	}
+	return localhost
+}
+
+// remote returns whether the Docker daemon of the endpoint is reached over the network.
+func (e DockerEndpoint) remote() bool {
+	return strings.HasPrefix(e.Host, "tcp://") || strings.HasPrefix(e.Host, "ssh://")
+}
+
+// env returns the environment variables that configure the docker CLI for the endpoint.
+// It is empty for the default endpoint, so that kind uses the environment of the process.
+func (e DockerEndpoint) env() map[string]string {
+	if e.Host == "" {
+		return nil
+	}
+	env := map[string]string{
+		envDockerHost:      e.Host,
+		envDockerTLSVerify: "",
+		envDockerCertPath:  "",
+	}
+	if e.TLSCertPath != "" {
+		env[envDockerTLSVerify] = "1"
+		env[envDockerCertPath] = e.TLSCertPath
+	}
+	return env
+}
+
+// cmdEnv returns the environment of docker commands for the endpoint. Unlike env, the Docker configuration is always
+// set explicitly, so that the commands are not affected by concurrent kind operations for other endpoints.
+func (e DockerEndpoint) cmdEnv() []string {
+	env := e.env()
+	if env == nil {
+		env = defaultDockerEnv
+	}
+	cmdEnv := slices.Clone(processEnv)
+	for _, key := range []string{envDockerHost, envDockerTLSVerify, envDockerCertPath} {
+		// Empty values are treated like unset variables by the docker CLI.
+		cmdEnv = append(cmdEnv, key+"="+env[key])
+	}
+	return cmdEnv
+}
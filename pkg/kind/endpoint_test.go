@@ -0,0 +1,109 @@
+package kind
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerEndpoint_env(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		endpoint DockerEndpoint
+		want     map[string]string
+	}{
+		{
+			desc:     "default endpoint",
+			endpoint: DockerEndpoint{},
+			want:     nil,
+		},
+		{
+			desc:     "unix socket",
+			endpoint: DockerEndpoint{Host: "unix:///run/user/1000/docker.sock"},
+			want: map[string]string{
+				envDockerHost:      "unix:///run/user/1000/docker.sock",
+				envDockerTLSVerify: "",
+				envDockerCertPath:  "",
+			},
+		},
+		{
+			desc:     "tcp with tls",
+			endpoint: DockerEndpoint{Host: "tcp://docker.example.com:2376", TLSCertPath: "/etc/docker-hosts/remote"},
+			want: map[string]string{
+				envDockerHost:      "tcp://docker.example.com:2376",
+				envDockerTLSVerify: "1",
+				envDockerCertPath:  "/etc/docker-hosts/remote",
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, tC.endpoint.env())
+		})
+	}
+}
+
+func Test_docker_endpoint(t *testing.T) {
+	endpoint := DockerEndpoint{Host: "tcp://docker.example.com:2376", TLSCertPath: "/certs"}
+	cmd := docker(WithDockerEndpoint(context.Background(), endpoint), "version")
+	assert.Subset(t, cmd.Env, []string{"DOCKER_HOST=tcp://docker.example.com:2376", "DOCKER_TLS_VERIFY=1", "DOCKER_CERT_PATH=/certs"})
+
+	cmd = docker(context.Background(), "version")
+	assert.Subset(t, cmd.Env, []string{"DOCKER_HOST=" + defaultDockerEnv[envDockerHost]})
+}
+
+func Test_docker_processEnv(t *testing.T) {
+	err := withEnv(map[string]string{envHTTPProxy: "http://proxy.example.com:3128", envDockerNetwork: "kind-isolated"}, func() error {
+		cmd := docker(context.Background(), "version")
+		assert.NotContains(t, cmd.Env, envHTTPProxy+"=http://proxy.example.com:3128")
+		assert.NotContains(t, cmd.Env, envDockerNetwork+"=kind-isolated")
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestDockerEndpoint_PublishHost(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		endpoint       DockerEndpoint
+		expectedHost   string
+		expectedListen string
+	}{
+		{
+			desc:           "default endpoint",
+			endpoint:       DockerEndpoint{},
+			expectedHost:   "127.0.0.1",
+			expectedListen: "127.0.0.1",
+		},
+		{
+			desc:           "unix socket",
+			endpoint:       DockerEndpoint{Host: "unix:///run/user/1000/docker.sock"},
+			expectedHost:   "127.0.0.1",
+			expectedListen: "127.0.0.1",
+		},
+		{
+			desc:           "tcp",
+			endpoint:       DockerEndpoint{Host: "tcp://docker.example.com:2376"},
+			expectedHost:   "docker.example.com",
+			expectedListen: "0.0.0.0",
+		},
+		{
+			desc:           "ssh",
+			endpoint:       DockerEndpoint{Host: "ssh://user@10.0.0.5"},
+			expectedHost:   "10.0.0.5",
+			expectedListen: "0.0.0.0",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.expectedHost, tC.endpoint.PublishHost())
+			assert.Equal(t, tC.expectedListen, tC.endpoint.listenAddress())
+		})
+	}
+}
+
+func TestDockerEndpoint_name(t *testing.T) {
+	assert.Equal(t, DefaultDockerEndpointName, DockerEndpoint{}.name())
+	assert.Equal(t, "remote", DockerEndpoint{Name: "remote", Host: "tcp://docker.example.com:2376"}.name())
+}
@@ -0,0 +1,66 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+)
+
+// nodeImageRepository is the repository of the kind node images.
+const nodeImageRepository = "kindest/node"
+
+// Host inspects the Docker host the kind clusters run on.
+type Host interface {
+	// DockerVersion returns the version of the Docker daemon. It fails if the daemon is not reachable.
+	DockerVersion(ctx context.Context) (string, error)
+	// NodeImages returns the kind node images that are available in the Docker daemon, sorted by name.
+	NodeImages(ctx context.Context) ([]string, error)
+	// NetworkSubnet returns the IPv4 subnet of the Docker network.
+	NetworkSubnet(ctx context.Context, network string) (net.IPNet, error)
+}
+
+// NewHost returns a Host for the Docker daemon the docker CLI is configured for.
+func NewHost() Host {
+	return dockerHost{}
+}
+
+type dockerHost struct{}
+
+// DockerVersion implements Host.
+func (dockerHost) DockerVersion(ctx context.Context) (string, error) {
+	out, err := docker(ctx, "version", "--format", "{{.Server.Version}}").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to get docker version: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// NodeImages implements Host.
+func (dockerHost) NodeImages(ctx context.Context) ([]string, error) {
+	out, err := docker(ctx, "image", "ls", "--filter", "reference="+nodeImageRepository, "--format", "{{.Repository}}:{{.Tag}}").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node images: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return parseImages(out), nil
+}
+
+// NetworkSubnet implements Host.
+func (dockerHost) NetworkSubnet(ctx context.Context, network string) (net.IPNet, error) {
+	return GetDockerV4Network(ctx, network)
+}
+
+// parseImages returns the distinct images of the output of docker image ls, sorted by name.
+// Images without tag are skipped.
+func parseImages(out []byte) []string {
+	images := []string{}
+	for _, image := range strings.Fields(string(out)) {
+		if strings.HasSuffix(image, ":<none>") || slices.Contains(images, image) {
+			continue
+		}
+		images = append(images, image)
+	}
+	slices.Sort(images)
+	return images
+}
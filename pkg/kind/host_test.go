@@ -0,0 +1,13 @@
+package kind
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseImages(t *testing.T) {
+	out := []byte("kindest/node:v1.36.1\nkindest/node:v1.33.1\nkindest/node:<none>\nkindest/node:v1.36.1\n")
+	assert.Equal(t, []string{"kindest/node:v1.33.1", "kindest/node:v1.36.1"}, parseImages(out))
+	assert.Empty(t, parseImages(nil))
+}
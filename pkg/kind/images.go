@@ -0,0 +1,160 @@
+package kind
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	kindexec "sigs.k8s.io/kind/pkg/exec"
+
+	"github.com/openmcp-project/cluster-provider-kind/pkg/tracing"
+)
+
+// LoadImage implements Provider.
+func (p *kindProvider) LoadImage(ctx context.Context, name, image string) (err error) {
+	ctx, span := tracing.Start(ctx, "kind.LoadImage", attribute.String(attrCluster, name), attribute.String("kind.image", image))
+	defer func() { tracing.End(span, err) }()
+
+	if err := docker(ctx, "image", "inspect", image).Run(); err != nil {
+		if out, err := docker(ctx, "image", "pull", image).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to pull image %s: %w: %s", image, err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "cluster-provider-kind-images")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	archive := filepath.Join(dir, "image.tar")
+	if out, err := docker(ctx, "image", "save", "-o", archive, image).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to save image %s: %w: %s", image, err, strings.TrimSpace(string(out)))
+	}
+
+	return p.LoadImageArchive(ctx, name, archive)
+}
+
+// LoadImageArchive implements Provider.
+// Only the nodes are listed with the environment of the endpoint. The archive is streamed into the nodes by docker
+// commands that carry the endpoint themselves, so that lockEnv is not held while the archive is transferred to
+// remote hosts.
+func (p *kindProvider) LoadImageArchive(ctx context.Context, name, file string) (err error) {
+	ctx, span := tracing.Start(ctx, "kind.LoadImageArchive", attribute.String(attrCluster, name))
+	defer func() { tracing.End(span, err) }()
+
+	var internalNodes []nodes.Node
+	err = withEnv(dockerEndpointFrom(ctx).env(), func() (err error) {
+		internalNodes, err = p.internal.ListInternalNodes(name)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, node := range internalNodes {
+		if err := loadImageArchive(&endpointNode{Node: node, ctx: ctx}, file); err != nil {
+			return fmt.Errorf("failed to load image archive into node %s: %w", node.String(), err)
+		}
+	}
+	return nil
+}
+
+func loadImageArchive(node nodes.Node, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	return nodeutils.LoadImageArchive(node, f)
+}
+
+// endpointNode runs the commands of a kind node with docker exec against the Docker endpoint of the context,
+// independent of the environment of the process.
+type endpointNode struct {
+	nodes.Node
+	ctx context.Context
+}
+
+// Command implements exec.Cmder.
+func (n *endpointNode) Command(command string, args ...string) kindexec.Cmd {
+	return n.CommandContext(n.ctx, command, args...)
+}
+
+// CommandContext implements exec.Cmder.
+func (n *endpointNode) CommandContext(ctx context.Context, command string, args ...string) kindexec.Cmd {
+	return &endpointNodeCmd{ctx: ctx, node: n.String(), command: command, args: args}
+}
+
+// endpointNodeCmd is a command run in a node by endpointNode.
+type endpointNodeCmd struct {
+	ctx     context.Context
+	node    string
+	command string
+	args    []string
+	env     []string
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+}
+
+// Run implements exec.Cmd.
+func (c *endpointNodeCmd) Run() error {
+	stderr := &bytes.Buffer{}
+	cmd := docker(c.ctx, c.dockerArgs()...)
+	cmd.Stdin = c.stdin
+	cmd.Stdout = c.stdout
+	cmd.Stderr = stderr
+	if c.stderr != nil {
+		cmd.Stderr = io.MultiWriter(c.stderr, stderr)
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run %s: %w: %s", c.command, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// dockerArgs returns the arguments of the docker exec command, like the nodes of kind run their commands.
+func (c *endpointNodeCmd) dockerArgs() []string {
+	args := []string{"exec", "--privileged"}
+	if c.stdin != nil {
+		args = append(args, "-i")
+	}
+	for _, env := range c.env {
+		args = append(args, "-e", env)
+	}
+	args = append(args, c.node, c.command)
+	return append(args, c.args...)
+}
+
+// SetEnv implements exec.Cmd.
+func (c *endpointNodeCmd) SetEnv(env ...string) kindexec.Cmd {
+	c.env = env
+	return c
+}
+
+// SetStdin implements exec.Cmd.
+func (c *endpointNodeCmd) SetStdin(r io.Reader) kindexec.Cmd {
+	c.stdin = r
+	return c
+}
+
+// SetStdout implements exec.Cmd.
+func (c *endpointNodeCmd) SetStdout(w io.Writer) kindexec.Cmd {
+	c.stdout = w
+	return c
+}
+
+// SetStderr implements exec.Cmd.
+func (c *endpointNodeCmd) SetStderr(w io.Writer) kindexec.Cmd {
+	c.stderr = w
+	return c
+}
@@ -0,0 +1,23 @@
+package kind
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_endpointNodeCmd_dockerArgs(t *testing.T) {
+	cmd := &endpointNodeCmd{
+		ctx:     context.Background(),
+		node:    "test-control-plane",
+		command: "ctr",
+		args:    []string{"--namespace=k8s.io", "images", "import", "-"},
+	}
+	cmd.SetStdin(strings.NewReader("archive")).SetEnv("KEY=value")
+
+	assert.Equal(t, []string{
+		"exec", "--privileged", "-i", "-e", "KEY=value", "test-control-plane", "ctr", "--namespace=k8s.io", "images", "import", "-",
+	}, cmd.dockerArgs())
+}
@@ -0,0 +1,170 @@
+package kind
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"sigs.k8s.io/kind/pkg/apis/config/defaults"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	kindversion "sigs.k8s.io/kind/pkg/cmd/kind/version"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/tracing"
+)
+
+var (
+	// AnnotationConfigHash is the annotation used to store the hash of the kind configuration a cluster has been created with.
+	AnnotationConfigHash = v1alpha1.SchemeGroupVersion.Group + "/config-hash"
+)
+
+// ClusterInfo describes the node containers of a kind cluster.
+type ClusterInfo struct {
+	// Nodes are the node containers, sorted by name.
+	Nodes []Node
+	// NodeImage is the image of the node containers.
+	NodeImage string
+	// KubernetesVersion is the Kubernetes version of the node image.
+	KubernetesVersion string
+	// CreationTimestamp is the time the first node container has been created.
+	CreationTimestamp time.Time
+}
+
+// Node describes a node container of a kind cluster.
+type Node struct {
+	// Name is the name of the container.
+	Name string
+	// ContainerID is the ID of the container.
+	ContainerID string
+	// IP is the IPv4 address of the container.
+	IP string
+	// Role is the kind role of the node, e.g. control-plane or worker.
+	Role string
+}
+
+// Version returns the version of kind the clusters are created with.
+func Version() string {
+	return "v" + kindversion.Version()
+}
+
+// KubernetesVersion returns the Kubernetes version of the default node image of kind, e.g. v1.36.1.
+func KubernetesVersion() string {
+	return ImageKubernetesVersion(defaults.Image)
+}
+
+// ImageKubernetesVersion returns the Kubernetes version of a kind node image, i.e. its tag, e.g. v1.33.1 for
+// kindest/node:v1.33.1@sha256:....
+func ImageKubernetesVersion(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// ClusterInfo implements Provider.
+func (p *kindProvider) ClusterInfo(ctx context.Context, name string) (_ ClusterInfo, err error) {
+	ctx, span := tracing.Start(ctx, "kind.ClusterInfo", attribute.String(attrCluster, name))
+	defer func() { tracing.End(span, err) }()
+
+	info := ClusterInfo{}
+	err = withEnv(dockerEndpointFrom(ctx).env(), func() error {
+		return p.clusterInfo(ctx, name, &info)
+	})
+	return info, err
+}
+
+func (p *kindProvider) clusterInfo(ctx context.Context, name string, info *ClusterInfo) error {
+	kindNodes, err := p.internal.ListNodes(name)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range kindNodes {
+		node, image, created, err := inspectNode(ctx, n)
+		if err != nil {
+			return err
+		}
+		info.Nodes = append(info.Nodes, node)
+		if info.CreationTimestamp.IsZero() || created.Before(info.CreationTimestamp) {
+			info.CreationTimestamp = created
+		}
+		if info.NodeImage == "" {
+			info.NodeImage = image
+		}
+	}
+	slices.SortFunc(info.Nodes, func(a, b Node) int { return strings.Compare(a.Name, b.Name) })
+
+	if len(kindNodes) > 0 {
+		info.KubernetesVersion, err = nodeutils.KubeVersion(kindNodes[0])
+		if err != nil {
+			return fmt.Errorf("failed to get Kubernetes version of cluster %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ConfigHash implements Provider.
+func (p *kindProvider) ConfigHash(_ context.Context, cfg ClusterConfig) (string, error) {
+	kindCfg, err := loadKindConfig(p.configFile)
+	if err != nil {
+		return "", err
+	}
+	applyClusterConfig(kindCfg, cfg)
+	return configHash(kindCfg)
+}
+
+// configHash returns a short hash of the kind configuration.
+func configHash(kindCfg *v1alpha4.Cluster) (string, error) {
+	data, err := yaml.Marshal(kindCfg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// inspectNode returns the description, the image and the creation time of the node container.
+func inspectNode(ctx context.Context, n nodes.Node) (Node, string, time.Time, error) {
+	role, err := n.Role()
+	if err != nil {
+		return Node{}, "", time.Time{}, err
+	}
+	ip, _, err := n.IP()
+	if err != nil {
+		return Node{}, "", time.Time{}, err
+	}
+
+	cmd := docker(ctx, "container", "inspect", "-f", "{{.Id}} {{.Config.Image}} {{.Created}}", n.String())
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return Node{}, "", time.Time{}, fmt.Errorf("failed to inspect node %s: %w: %s", n.String(), err, strings.TrimSpace(string(out)))
+	}
+	id, image, created, err := parseNodeInspect(string(out))
+	if err != nil {
+		return Node{}, "", time.Time{}, fmt.Errorf("failed to inspect node %s: %w", n.String(), err)
+	}
+
+	return Node{Name: n.String(), ContainerID: id, IP: ip, Role: role}, image, created, nil
+}
+
+// parseNodeInspect parses the ID, image and creation time from the output of `docker container inspect`.
+func parseNodeInspect(out string) (string, string, time.Time, error) {
+	fields := strings.Fields(out)
+	if len(fields) != 3 {
+		return "", "", time.Time{}, fmt.Errorf("unexpected output %q", strings.TrimSpace(out))
+	}
+	created, err := time.Parse(time.RFC3339Nano, fields[2])
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	return fields[0], fields[1], created, nil
+}
@@ -0,0 +1,42 @@
+package kind
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseNodeInspect(t *testing.T) {
+	id, image, created, err := parseNodeInspect("abc123 kindest/node:v1.33.1 2025-06-01T12:00:00.123456789Z\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", id)
+	assert.Equal(t, "kindest/node:v1.33.1", image)
+	assert.Equal(t, time.Date(2025, 6, 1, 12, 0, 0, 123456789, time.UTC), created)
+
+	_, _, _, err = parseNodeInspect("abc123")
+	assert.Error(t, err)
+}
+
+func Test_configHash(t *testing.T) {
+	kindCfg, err := loadKindConfig("")
+	assert.NoError(t, err)
+	hash, err := configHash(kindCfg)
+	assert.NoError(t, err)
+	assert.Len(t, hash, 16)
+
+	applyClusterConfig(kindCfg, ClusterConfig{PodSubnet: "10.100.0.0/16"})
+	changed, err := configHash(kindCfg)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+}
+
+func TestKubernetesVersion(t *testing.T) {
+	assert.Regexp(t, `^v1\.\d+\.\d+$`, KubernetesVersion())
+}
+
+func TestImageKubernetesVersion(t *testing.T) {
+	assert.Equal(t, "v1.33.1", ImageKubernetesVersion("kindest/node:v1.33.1"))
+	assert.Equal(t, "v1.33.1", ImageKubernetesVersion("registry.local:5000/kindest/node:v1.33.1@sha256:050072256b9a903bd914c0b2866828150cb229cea0efe5892e2b644d5dd3b34f"))
+	assert.Empty(t, ImageKubernetesVersion("registry.local:5000/kindest/node"))
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"net"
-	"os/exec"
 	"slices"
 	"strings"
 	"sync"
@@ -13,14 +12,19 @@ import (
 	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
 
 	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/metrics"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
-	networkName = "kind"
-	subnetMin   = 200
-	subnetMax   = 255
+	// DefaultNetworkName is the Docker network kind creates the clusters in, unless they are isolated.
+	DefaultNetworkName = "kind"
+
+	subnetMin = 200
+	subnetMax = 255
+
+	lbSubnetsTotal = subnetMax - subnetMin + 1
 )
 
 var (
@@ -34,8 +38,8 @@ var (
 	lockListClusters         = sync.Mutex{}
 )
 
-func getDockerContainerIP(containerName string) (net.IP, error) {
-	cmd := exec.Command("docker", "container", "inspect", "-f", "{{range.NetworkSettings.Networks}}{{.IPAddress}}{{end}}", containerName)
+func getDockerContainerIP(ctx context.Context, containerName string) (net.IP, error) {
+	cmd := docker(ctx, "container", "inspect", "-f", "{{range.NetworkSettings.Networks}}{{.IPAddress}}{{end}}", containerName)
 	cmdOut, err := cmd.Output()
 	if err != nil {
 		return net.IP{}, err
@@ -49,9 +53,9 @@ func getDockerContainerIP(containerName string) (net.IP, error) {
 	return parsed, nil
 }
 
-// GetDockerV4Network retrieves the IPv4 network configuration of the Docker network named "kind".
-func GetDockerV4Network(ctx context.Context) (net.IPNet, error) {
-	cmd := exec.CommandContext(ctx, "docker", "network", "inspect", "-f", "json", networkName)
+// GetDockerV4Network retrieves the IPv4 network configuration of the given Docker network.
+func GetDockerV4Network(ctx context.Context, network string) (net.IPNet, error) {
+	cmd := docker(ctx, "network", "inspect", "-f", "json", network)
 	cmdOut, err := cmd.Output()
 	if err != nil {
 		return net.IPNet{}, err
@@ -84,12 +88,12 @@ func isIPv4(ipNet *net.IPNet) bool {
 	return ipNet.IP.To4() != nil
 }
 
-// NextAvailableLBNetwork finds the next available subnet for MetalLB in the Docker network.
-func NextAvailableLBNetwork(ctx context.Context, c client.Client) (net.IPNet, error) {
+// NextAvailableLBNetwork finds the next available subnet for MetalLB in the given Docker network.
+func NextAvailableLBNetwork(ctx context.Context, c client.Client, network string) (net.IPNet, error) {
 	lockListClusters.Lock()
 	defer lockListClusters.Unlock()
 
-	kindNetwork, err := GetDockerV4Network(ctx)
+	kindNetwork, err := GetDockerV4Network(ctx, network)
 	if err != nil {
 		return net.IPNet{}, err
 	}
@@ -99,22 +103,76 @@ func NextAvailableLBNetwork(ctx context.Context, c client.Client) (net.IPNet, er
 		return net.IPNet{}, err
 	}
 
+	// All subnets are checked, so that the utilization of the pool can be reported.
+	free, err := FreeLBSubnets(kindNetwork, dockerEndpointFrom(ctx).name(), network, clusters)
+	if err != nil {
+		return net.IPNet{}, err
+	}
+
+	if len(free) == 0 {
+		ObserveLBSubnets(ctx, network, 0)
+		return net.IPNet{}, errNoSubnetsAvailable
+	}
+	// The returned subnet is assigned by the caller.
+	ObserveLBSubnets(ctx, network, len(free)-1)
+	return free[0], nil
+}
+
+// ObserveLBSubnets reports the utilization of the LoadBalancer subnets of the Docker network on the Docker host of the
+// context, given the number of free subnets.
+func ObserveLBSubnets(ctx context.Context, network string, free int) {
+	host := dockerEndpointFrom(ctx).name()
+	metrics.LoadBalancerSubnetsUsed.WithLabelValues(host, network).Set(float64(lbSubnetsTotal - free))
+	metrics.LoadBalancerSubnetsTotal.WithLabelValues(host, network).Set(float64(lbSubnetsTotal))
+}
+
+// FreeLBSubnets returns the LoadBalancer subnets of the Docker network that are not assigned to any of the clusters
+// in the network on the given Docker host. Clusters on other hosts are ignored, since their networks may have the same
+// subnet.
+func FreeLBSubnets(kindNetwork net.IPNet, host, network string, clusters *clustersv1alpha1.ClusterList) ([]net.IPNet, error) {
+	inNetwork := []clustersv1alpha1.Cluster{}
+	for _, c := range clusters.Items {
+		clusterHost, err := DockerHostFromCluster(&c)
+		if err != nil {
+			return nil, err
+		}
+		if clusterHost == host && NetworkFromCluster(&c) == network {
+			inNetwork = append(inNetwork, c)
+		}
+	}
+
+	free := []net.IPNet{}
 	for i := subnetMin; i <= subnetMax; i++ {
 		subnet, err := calculateV4Subnet(kindNetwork, i)
 		if err != nil {
-			return net.IPNet{}, err
+			return nil, err
 		}
 
-		taken, err := isIPNetTaken(subnet, clusters)
+		taken, err := isIPNetTaken(subnet, inNetwork)
 		if err != nil {
-			return net.IPNet{}, err
+			return nil, err
 		}
-		if taken {
-			continue
+		if !taken {
+			free = append(free, subnet)
 		}
-		return subnet, nil
 	}
-	return net.IPNet{}, errNoSubnetsAvailable
+	return free, nil
+}
+
+// DockerHostFromCluster returns the name of the Docker host the cluster has been placed on. Clusters without a Docker
+// host in their provider status run on the Docker daemon of the provider, which is named DefaultDockerEndpointName.
+func DockerHostFromCluster(c *clustersv1alpha1.Cluster) (string, error) {
+	if c.Status.ProviderStatus == nil || len(c.Status.ProviderStatus.Raw) == 0 {
+		return DefaultDockerEndpointName, nil
+	}
+	status := v1alpha1.ClusterStatus{}
+	if err := c.Status.GetProviderStatus(&status); err != nil {
+		return "", err
+	}
+	if status.DockerHost == "" {
+		return DefaultDockerEndpointName, nil
+	}
+	return status.DockerHost, nil
 }
 
 // calculateV4Subnet returns a subnet of the given net.IPNet. Must be a /8 or /16 network.
@@ -138,8 +196,8 @@ func calculateV4Subnet(input net.IPNet, offset int) (net.IPNet, error) {
 	}, nil
 }
 
-func isIPNetTaken(ipnet net.IPNet, clusters *clustersv1alpha1.ClusterList) (bool, error) {
-	for _, c := range clusters.Items {
+func isIPNetTaken(ipnet net.IPNet, clusters []clustersv1alpha1.Cluster) (bool, error) {
+	for _, c := range clusters {
 		cNet, err := SubnetFromCluster(&c)
 		if err != nil {
 			return false, err
@@ -5,6 +5,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
 )
 
 func Test_parseDockerV4Network(t *testing.T) {
@@ -69,6 +74,36 @@ func Test_calculateV4Subnet(t *testing.T) {
 	}
 }
 
+func TestFreeLBSubnets(t *testing.T) {
+	remote := clustersv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "remote", Annotations: map[string]string{AnnotationAssignedSubnet: "172.19.201.0/24"}}}
+	assert.NoError(t, remote.Status.SetProviderStatus(v1alpha1.ClusterStatus{DockerHost: "remote"}))
+	clusters := &clustersv1alpha1.ClusterList{Items: []clustersv1alpha1.Cluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "one", Annotations: map[string]string{AnnotationAssignedSubnet: "172.19.200.0/24"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "two", Annotations: map[string]string{AnnotationAssignedSubnet: "172.19.202.0/24"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "three"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "isolated", Annotations: map[string]string{
+			AnnotationAssignedSubnet: "172.19.203.0/24",
+			AnnotationNetwork:        "kind-isolated",
+		}}},
+		remote,
+	}}
+
+	free, err := FreeLBSubnets(mustParseCIDR("172.19.0.0/16"), DefaultDockerEndpointName, DefaultNetworkName, clusters)
+	assert.NoError(t, err)
+	assert.Len(t, free, lbSubnetsTotal-2)
+	assertEqualIPNet(t, free[0], mustParseCIDR("172.19.201.0/24"))
+	assertEqualIPNet(t, free[1], mustParseCIDR("172.19.203.0/24"))
+
+	free, err = FreeLBSubnets(mustParseCIDR("172.19.0.0/16"), "remote", DefaultNetworkName, clusters)
+	assert.NoError(t, err)
+	assert.Len(t, free, lbSubnetsTotal-1)
+	assertEqualIPNet(t, free[0], mustParseCIDR("172.19.200.0/24"))
+	assertEqualIPNet(t, free[1], mustParseCIDR("172.19.202.0/24"))
+
+	_, err = FreeLBSubnets(mustParseCIDR("10.43.8.64/28"), DefaultDockerEndpointName, DefaultNetworkName, clusters)
+	assert.ErrorIs(t, err, errUnsupportedNetwork)
+}
+
 func assertEqualIPNet(t *testing.T, a, b net.IPNet) {
 	assert.True(t, ipNetEqual(&a, &b), "IP networks are not equal: %s != %s", a.String(), b.String())
 }
@@ -0,0 +1,247 @@
+package kind
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+)
+
+const (
+	// envDockerNetwork makes kind create the nodes in the given network instead of the default network.
+	envDockerNetwork = "KIND_EXPERIMENTAL_DOCKER_NETWORK"
+)
+
+var (
+	// AnnotationNetwork is the annotation used to store the Docker network a cluster is created in.
+	// Clusters without the annotation are created in the default network.
+	AnnotationNetwork = v1alpha1.SchemeGroupVersion.Group + "/network"
+
+	errNoNetworkSubnetsAvailable = errors.New("no subnets available for Docker networks")
+
+	// networkSubnetCandidates are the /16 subnets networks for isolated clusters are created with, so that LoadBalancer
+	// subnets can be allocated in them. The pod and service subnets of kind are left out.
+	networkSubnetCandidates = func() []net.IPNet {
+		candidates := []net.IPNet{}
+		for i := 18; i <= 31; i++ {
+			candidates = append(candidates, net.IPNet{IP: net.IPv4(172, byte(i), 0, 0).To4(), Mask: net.CIDRMask(16, 32)})
+		}
+		for i := 128; i <= 239; i++ {
+			candidates = append(candidates, net.IPNet{IP: net.IPv4(10, byte(i), 0, 0).To4(), Mask: net.CIDRMask(16, 32)})
+		}
+		return candidates
+	}()
+
+	lockNetworks = sync.Mutex{}
+)
+
+// NetworkFromCluster returns the Docker network of the cluster.
+func NetworkFromCluster(c *clustersv1alpha1.Cluster) string {
+	if network, ok := c.Annotations[AnnotationNetwork]; ok && network != "" {
+		return network
+	}
+	return DefaultNetworkName
+}
+
+// EnsureNetwork makes sure the Docker network for isolated clusters exists. It is created with a /16 subnet that does
+// not overlap with any other Docker network. The default network is created by kind.
+func EnsureNetwork(ctx context.Context, name string) error {
+	if name == DefaultNetworkName {
+		return nil
+	}
+
+	lockNetworks.Lock()
+	defer lockNetworks.Unlock()
+
+	if err := docker(ctx, "network", "inspect", name).Run(); err == nil {
+		return nil
+	}
+
+	networks, err := listNetworks(ctx)
+	if err != nil {
+		return err
+	}
+	subnet, err := freeNetworkSubnet(networks)
+	if err != nil {
+		return err
+	}
+
+	cmd := docker(ctx, "network", "create",
+		"--driver", "bridge",
+		"--subnet", subnet.String(),
+		"--opt", "com.docker.network.bridge.enable_ip_masquerade=true",
+		"--label", labelManagedBy+"="+labelManagedByValue,
+		name,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create network %s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// DeleteNetwork removes the Docker network of isolated clusters once no kind node is attached to it anymore.
+// Router containers in the network are removed, other containers that are managed by the provider, like the local
+// registry, are disconnected. Networks that are not managed by the provider are kept. The default network is kept as
+// well, but its router containers are removed once no kind node is attached to it anymore, since the shared router of
+// the default network would otherwise outlive its last cluster.
+func DeleteNetwork(ctx context.Context, name string) error {
+	lockNetworks.Lock()
+	defer lockNetworks.Unlock()
+
+	cmd := docker(ctx, "network", "inspect", "-f", "json", name)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if bytes.Contains(bytes.ToLower(out), []byte("not found")) {
+			return nil
+		}
+		return fmt.Errorf("failed to inspect network %s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+
+	networks := []Network{}
+	if err := json.Unmarshal(out, &networks); err != nil {
+		return err
+	}
+	if len(networks) == 0 {
+		return nil
+	}
+
+	containers := map[string]map[string]string{}
+	for _, c := range networks[0].Containers {
+		labels, err := getDockerContainerLabels(ctx, c.Name)
+		if errors.Is(err, errContainerNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		containers[c.Name] = labels
+	}
+
+	plan, ok := planNetworkCleanup(name, networks[0].Labels, containers)
+	if !ok {
+		return nil
+	}
+
+	for _, c := range plan.remove {
+		if out, err := docker(ctx, "container", "rm", "--force", c).CombinedOutput(); err != nil && !isNoSuchContainer(out) {
+			return fmt.Errorf("failed to remove container %s from network %s: %w: %s", c, name, err, strings.TrimSpace(string(out)))
+		}
+	}
+	for _, c := range plan.disconnect {
+		if out, err := docker(ctx, "network", "disconnect", "--force", name, c).CombinedOutput(); err != nil && !isNoSuchContainer(out) {
+			return fmt.Errorf("failed to detach container %s from network %s: %w: %s", c, name, err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	if !plan.removeNetwork {
+		return nil
+	}
+	if out, err := docker(ctx, "network", "rm", name).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove network %s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// networkCleanup describes how a network is cleaned up once no kind node is attached to it anymore.
+type networkCleanup struct {
+	// remove are the containers that are removed.
+	remove []string
+	// disconnect are the containers that are disconnected from the network.
+	disconnect []string
+	// removeNetwork is true if the network itself is removed.
+	removeNetwork bool
+}
+
+// planNetworkCleanup returns how the network with the given labels is cleaned up. The containers attached to the
+// network are given with their labels. It returns false if nothing is to be done, because the network is not managed
+// by the provider or kind nodes or foreign containers are still attached to it.
+func planNetworkCleanup(name string, networkLabels map[string]string, containers map[string]map[string]string) (networkCleanup, bool) {
+	plan := networkCleanup{}
+	if name != DefaultNetworkName && networkLabels[labelManagedBy] != labelManagedByValue {
+		return plan, false
+	}
+
+	for c, labels := range containers {
+		switch {
+		case labels[labelManagedBy] != labelManagedByValue:
+			// kind nodes or foreign containers still use the network
+			return networkCleanup{}, false
+		case labels[labelRouterConfigSum] != "":
+			plan.remove = append(plan.remove, c)
+		case name == DefaultNetworkName:
+			// the registry and other containers of the provider stay in the default network
+		case c == RegistryName:
+			plan.disconnect = append(plan.disconnect, c)
+		default:
+			plan.remove = append(plan.remove, c)
+		}
+	}
+	slices.Sort(plan.remove)
+	slices.Sort(plan.disconnect)
+	plan.removeNetwork = name != DefaultNetworkName
+	return plan, true
+}
+
+// connectNetwork connects the container to the network. It does not fail if the container is already connected.
+func connectNetwork(ctx context.Context, container, network string) error {
+	cmd := docker(ctx, "network", "connect", network, container)
+	if out, err := cmd.CombinedOutput(); err != nil && !bytes.Contains(out, []byte("already exists")) {
+		return fmt.Errorf("failed to connect container %s to network %s: %w: %s", container, network, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// listNetworks returns all Docker networks.
+func listNetworks(ctx context.Context) ([]Network, error) {
+	ids, err := docker(ctx, "network", "ls", "--quiet").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list networks: %w", err)
+	}
+
+	args := append([]string{"network", "inspect", "-f", "json"}, strings.Fields(string(ids))...)
+	out, err := docker(ctx, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect networks: %w", err)
+	}
+
+	networks := []Network{}
+	if err := json.Unmarshal(out, &networks); err != nil {
+		return nil, err
+	}
+	return networks, nil
+}
+
+// freeNetworkSubnet returns the first candidate subnet that does not overlap with the subnets of the given networks.
+func freeNetworkSubnet(networks []Network) (net.IPNet, error) {
+	used := []*net.IPNet{}
+	for _, n := range networks {
+		for _, cfg := range n.IPAM.Config {
+			if _, subnet, err := net.ParseCIDR(cfg.Subnet); err == nil {
+				used = append(used, subnet)
+			}
+		}
+	}
+
+	for _, candidate := range networkSubnetCandidates {
+		overlaps := false
+		for _, subnet := range used {
+			if subnet.Contains(candidate.IP) || candidate.Contains(subnet.IP) {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			return candidate, nil
+		}
+	}
+	return net.IPNet{}, errNoNetworkSubnetsAvailable
+}
@@ -0,0 +1,117 @@
+package kind
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+)
+
+func Test_freeNetworkSubnet(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		subnets     []string
+		expectedNet net.IPNet
+		expectedErr error
+	}{
+		{
+			desc:        "should return first candidate without networks",
+			subnets:     nil,
+			expectedNet: mustParseCIDR("172.18.0.0/16"),
+		},
+		{
+			desc:        "should skip overlapping subnets",
+			subnets:     []string{"172.17.0.0/16", "172.18.0.0/16", "172.19.128.0/20", "fc00:f853:ccd:e793::/64"},
+			expectedNet: mustParseCIDR("172.20.0.0/16"),
+		},
+		{
+			desc:        "should skip candidates within larger subnets",
+			subnets:     []string{"172.16.0.0/12"},
+			expectedNet: mustParseCIDR("10.128.0.0/16"),
+		},
+		{
+			desc:        "should fail if all candidates are used",
+			subnets:     []string{"172.16.0.0/12", "10.0.0.0/8"},
+			expectedErr: errNoNetworkSubnetsAvailable,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			network := Network{}
+			for _, s := range tC.subnets {
+				network.IPAM.Config = append(network.IPAM.Config, IPAMConfig{Subnet: s})
+			}
+
+			actualNet, actualErr := freeNetworkSubnet([]Network{network})
+			assert.Equal(t, tC.expectedErr, actualErr)
+			if tC.expectedErr == nil {
+				assertEqualIPNet(t, actualNet, tC.expectedNet)
+			}
+		})
+	}
+}
+
+func TestNetworkFromCluster(t *testing.T) {
+	cluster := &clustersv1alpha1.Cluster{}
+	assert.Equal(t, DefaultNetworkName, NetworkFromCluster(cluster))
+
+	cluster.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{AnnotationNetwork: "kind-tenant-a"}}
+	assert.Equal(t, "kind-tenant-a", NetworkFromCluster(cluster))
+}
+
+func Test_planNetworkCleanup(t *testing.T) {
+	managed := map[string]string{labelManagedBy: labelManagedByValue}
+	router := map[string]string{labelManagedBy: labelManagedByValue, labelRouterConfigSum: "abc"}
+	node := map[string]string{"io.x-k8s.kind.cluster": "test"}
+
+	testCases := []struct {
+		desc          string
+		network       string
+		networkLabels map[string]string
+		containers    map[string]map[string]string
+		expected      networkCleanup
+		expectedOK    bool
+	}{
+		{
+			desc:          "should remove routers and the network and disconnect the registry",
+			network:       "isolated",
+			networkLabels: managed,
+			containers:    map[string]map[string]string{"isolated-frr": router, RegistryName: managed, "other": managed},
+			expected:      networkCleanup{remove: []string{"isolated-frr", "other"}, disconnect: []string{RegistryName}, removeNetwork: true},
+			expectedOK:    true,
+		},
+		{
+			desc:          "should keep networks with kind nodes",
+			network:       "isolated",
+			networkLabels: managed,
+			containers:    map[string]map[string]string{"isolated-frr": router, "test-control-plane": node},
+		},
+		{
+			desc:       "should keep networks not managed by the provider",
+			network:    "foreign",
+			containers: map[string]map[string]string{"foreign-frr": router},
+		},
+		{
+			desc:       "should only remove routers from the default network",
+			network:    DefaultNetworkName,
+			containers: map[string]map[string]string{"kind-frr": router, "test-frr": router, RegistryName: managed, "dns": managed},
+			expected:   networkCleanup{remove: []string{"kind-frr", "test-frr"}},
+			expectedOK: true,
+		},
+		{
+			desc:       "should keep the shared router of the default network while kind nodes are attached",
+			network:    DefaultNetworkName,
+			containers: map[string]map[string]string{"kind-frr": router, "test-control-plane": node},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			actual, ok := planNetworkCleanup(tC.network, tC.networkLabels, tC.containers)
+			assert.Equal(t, tC.expectedOK, ok)
+			assert.Equal(t, tC.expected, actual)
+		})
+	}
+}
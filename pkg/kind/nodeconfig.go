@@ -0,0 +1,157 @@
+package kind
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+const (
+	envHTTPProxy  = "HTTP_PROXY"
+	envHTTPSProxy = "HTTPS_PROXY"
+	envNoProxy    = "NO_PROXY"
+
+	caCertificatesDir = "/usr/local/share/ca-certificates"
+)
+
+// lockEnv serializes kind operations that pass settings like the proxy, network or Docker endpoint to kind, since kind
+// reads them from the environment of the process. Operations that use the environment of the process run concurrently.
+// Operations on remote Docker hosts always pass the endpoint, so they block all other kind operations on every host
+// while they run. Holders of the exclusive lock must therefore not wait for anything that does not need the
+// environment, e.g. CreateCluster waits for the nodes to become ready after releasing it.
+var lockEnv = sync.RWMutex{}
+
+// RegistryMirror configures the endpoints from which the nodes pull the images of a registry.
+type RegistryMirror struct {
+	// Registry is the host of the mirrored registry, e.g. docker.io.
+	Registry string
+	// Endpoints are the URLs of the mirrors, e.g. https://mirror.example.com. They are tried in order before the
+	// registry itself.
+	Endpoints []string
+}
+
+// ProxyConfig configures the proxy used by the nodes.
+type ProxyConfig struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	// NoProxy lists hosts, domains and CIDRs that are reached without the proxy.
+	// kind adds the node names, the kind network and the pod and service subnets.
+	NoProxy []string
+}
+
+// env returns the proxy environment variables in upper and lower case.
+func (p *ProxyConfig) env() map[string]string {
+	env := map[string]string{}
+	if p == nil {
+		return env
+	}
+	set := func(key, value string) {
+		if value != "" {
+			env[key] = value
+			env[strings.ToLower(key)] = value
+		}
+	}
+	set(envHTTPProxy, p.HTTPProxy)
+	set(envHTTPSProxy, p.HTTPSProxy)
+	set(envNoProxy, strings.Join(p.NoProxy, ","))
+	return env
+}
+
+// withEnv runs fn with the given environment variables set in the process.
+// The previous values are restored afterwards.
+func withEnv(env map[string]string, fn func() error) error {
+	if len(env) == 0 {
+		lockEnv.RLock()
+		defer lockEnv.RUnlock()
+		return fn()
+	}
+
+	lockEnv.Lock()
+	defer lockEnv.Unlock()
+
+	for key, value := range env {
+		if previous, ok := os.LookupEnv(key); ok {
+			defer os.Setenv(key, previous) //nolint:errcheck
+		} else {
+			defer os.Unsetenv(key) //nolint:errcheck
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	return fn()
+}
+
+// configureNodes writes the registry mirrors and CA certificates to all nodes of the given kind cluster.
+// containerd is restarted to trust the CA certificates.
+func configureNodes(ctx context.Context, clusterName string, cfg ClusterConfig) error {
+	if len(cfg.RegistryMirrors) == 0 && len(cfg.CACertificates) == 0 {
+		return nil
+	}
+
+	nodes, err := clusterNodes(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		for _, mirror := range cfg.RegistryMirrors {
+			if err := writeHostsTOML(ctx, node, mirror.Registry, mirror.Endpoints); err != nil {
+				return err
+			}
+		}
+
+		if len(cfg.CACertificates) == 0 {
+			continue
+		}
+		for i, cert := range cfg.CACertificates {
+			file := path.Join(caCertificatesDir, fmt.Sprintf("cluster-provider-kind-%d.crt", i))
+			if err := writeNodeFile(ctx, node, file, cert); err != nil {
+				return fmt.Errorf("failed to write CA certificate on node %s: %w", node, err)
+			}
+		}
+		if err := execNode(ctx, node, "update-ca-certificates && systemctl restart containerd"); err != nil {
+			return fmt.Errorf("failed to trust CA certificates on node %s: %w", node, err)
+		}
+	}
+	return nil
+}
+
+// writeHostsTOML configures containerd on the given node to pull the images of host from the given endpoints.
+// It requires the kind cluster to be created with RegistryContainerdConfigPatch.
+func writeHostsTOML(ctx context.Context, node, host string, endpoints []string) error {
+	if err := writeNodeFile(ctx, node, path.Join(registryCertsDir, host, "hosts.toml"), hostsTOML(endpoints)); err != nil {
+		return fmt.Errorf("failed to configure registry mirror on node %s: %w", node, err)
+	}
+	return nil
+}
+
+func hostsTOML(endpoints []string) string {
+	out := strings.Builder{}
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(&out, "[host.%q]\n  capabilities = [\"pull\", \"resolve\"]\n", endpoint)
+	}
+	return out.String()
+}
+
+// writeNodeFile writes the content to the file on the given node, creating the parent directory.
+func writeNodeFile(ctx context.Context, node, file, content string) error {
+	cmd := docker(ctx, "exec", "-i", node, "sh", "-c",
+		fmt.Sprintf("mkdir -p %q && cat > %q", path.Dir(file), file))
+	cmd.Stdin = strings.NewReader(content)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func execNode(ctx context.Context, node, script string) error {
+	cmd := docker(ctx, "exec", node, "sh", "-c", script)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package kind
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyConfig_env(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		proxy    *ProxyConfig
+		expected map[string]string
+	}{
+		{
+			desc:     "should return empty environment without proxy",
+			proxy:    nil,
+			expected: map[string]string{},
+		},
+		{
+			desc: "should join no proxy list",
+			proxy: &ProxyConfig{
+				HTTPProxy: "http://proxy.example.com:3128",
+				NoProxy:   []string{"example.com", "172.18.0.0/16"},
+			},
+			expected: map[string]string{
+				"HTTP_PROXY": "http://proxy.example.com:3128",
+				"http_proxy": "http://proxy.example.com:3128",
+				"NO_PROXY":   "example.com,172.18.0.0/16",
+				"no_proxy":   "example.com,172.18.0.0/16",
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.expected, tC.proxy.env())
+		})
+	}
+}
+
+func Test_withEnv(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "http://previous")
+	os.Unsetenv("http_proxy") //nolint:errcheck
+
+	err := withEnv(map[string]string{"HTTP_PROXY": "http://proxy", "http_proxy": "http://proxy"}, func() error {
+		assert.Equal(t, "http://proxy", os.Getenv("HTTP_PROXY"))
+		assert.Equal(t, "http://proxy", os.Getenv("http_proxy"))
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "http://previous", os.Getenv("HTTP_PROXY"))
+	_, ok := os.LookupEnv("http_proxy")
+	assert.False(t, ok)
+}
+
+func Test_hostsTOML(t *testing.T) {
+	expected := `[host."https://mirror.example.com"]
+  capabilities = ["pull", "resolve"]
+[host."http://kind-registry:5000"]
+  capabilities = ["pull", "resolve"]
+`
+	assert.Equal(t, expected, hostsTOML([]string{"https://mirror.example.com", "http://kind-registry:5000"}))
+}
@@ -0,0 +1,116 @@
+package kind
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+)
+
+const (
+	hostPortMin = 20000
+	hostPortMax = 29999
+)
+
+var (
+	errNoHostPortsAvailable = errors.New("no host ports available")
+
+	// AnnotationHostPorts is the annotation used to store the host ports assigned to a cluster
+	AnnotationHostPorts = v1alpha1.SchemeGroupVersion.Group + "/host-ports"
+
+	publishedPortRegexp = regexp.MustCompile(`:(\d+)->`)
+)
+
+// AssignHostPorts assigns host ports to the given port mappings that are neither assigned to another cluster
+// nor published by any container of the Docker host.
+func AssignHostPorts(ctx context.Context, c client.Client, mappings []PortMapping) ([]PortMapping, error) {
+	lockListClusters.Lock()
+	defer lockListClusters.Unlock()
+
+	used, err := publishedHostPorts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := &clustersv1alpha1.ClusterList{}
+	if err := c.List(ctx, clusters); err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters.Items {
+		assigned, err := HostPortsFromCluster(&cluster)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range assigned {
+			used[m.HostPort] = true
+		}
+	}
+
+	result := make([]PortMapping, 0, len(mappings))
+	port := int32(hostPortMin)
+	for _, m := range mappings {
+		for used[port] {
+			port++
+		}
+		if port > hostPortMax {
+			return nil, errNoHostPortsAvailable
+		}
+		m.HostPort = port
+		used[port] = true
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// HostPortsFromCluster extracts the assigned host ports from the cluster annotations.
+func HostPortsFromCluster(c *clustersv1alpha1.Cluster) ([]PortMapping, error) {
+	value, ok := c.Annotations[AnnotationHostPorts]
+	if !ok {
+		return nil, nil
+	}
+
+	mappings := []PortMapping{}
+	err := json.Unmarshal([]byte(value), &mappings)
+	return mappings, err
+}
+
+// URL returns the URL under which the host port of the mapping is reachable on the Docker host of the context.
+func (m PortMapping) URL(ctx context.Context) string {
+	return m.Scheme + "://" + net.JoinHostPort(dockerEndpointFrom(ctx).PublishHost(), strconv.Itoa(int(m.HostPort)))
+}
+
+// EncodeHostPorts returns the annotation value for the given port mappings.
+func EncodeHostPorts(mappings []PortMapping) (string, error) {
+	data, err := json.Marshal(mappings)
+	return string(data), err
+}
+
+// publishedHostPorts returns the host ports that are published by containers of the Docker host.
+func publishedHostPorts(ctx context.Context) (map[int32]bool, error) {
+	cmd := docker(ctx, "container", "ls", "--all", "--format", "{{.Ports}}")
+	cmdOut, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePublishedPorts(string(cmdOut)), nil
+}
+
+func parsePublishedPorts(out string) map[int32]bool {
+	ports := map[int32]bool{}
+	for _, match := range publishedPortRegexp.FindAllStringSubmatch(out, -1) {
+		port, err := strconv.ParseInt(match[1], 10, 32)
+		if err != nil {
+			continue
+		}
+		ports[int32(port)] = true
+	}
+	return ports
+}
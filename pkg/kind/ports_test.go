@@ -0,0 +1,40 @@
+package kind
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+)
+
+func Test_parsePublishedPorts(t *testing.T) {
+	out := "127.0.0.1:41234->6443/tcp\n0.0.0.0:20000->80/tcp, [::]:20000->80/tcp, 0.0.0.0:20001->443/tcp\n\n"
+	assert.Equal(t, map[int32]bool{41234: true, 20000: true, 20001: true}, parsePublishedPorts(out))
+}
+
+func TestHostPortsFromCluster(t *testing.T) {
+	mappings := []PortMapping{{Name: "ingress-http", Scheme: "http", ContainerPort: 80, HostPort: 20000}}
+	value, err := EncodeHostPorts(mappings)
+	assert.NoError(t, err)
+
+	cluster := &clustersv1alpha1.Cluster{}
+	actual, err := HostPortsFromCluster(cluster)
+	assert.NoError(t, err)
+	assert.Nil(t, actual)
+
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, AnnotationHostPorts, value)
+	actual, err = HostPortsFromCluster(cluster)
+	assert.NoError(t, err)
+	assert.Equal(t, mappings, actual)
+}
+
+func TestPortMapping_URL(t *testing.T) {
+	m := PortMapping{Name: "ingress-http", Scheme: "http", ContainerPort: 80, HostPort: 20000}
+	assert.Equal(t, "http://127.0.0.1:20000", m.URL(context.Background()))
+
+	ctx := WithDockerEndpoint(context.Background(), DockerEndpoint{Host: "tcp://docker.example.com:2376"})
+	assert.Equal(t, "http://docker.example.com:20000", m.URL(ctx))
+}
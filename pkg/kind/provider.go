@@ -1,7 +1,10 @@
 package kind
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path"
 	"strings"
@@ -9,29 +12,53 @@ import (
 
 	"slices"
 
+	"go.opentelemetry.io/otel/attribute"
 	"sigs.k8s.io/kind/pkg/cluster"
+
+	"github.com/openmcp-project/cluster-provider-kind/pkg/tracing"
 )
 
 // Provider defines the interface for managing Kubernetes clusters using kind.
 // It provides methods to create, delete, check existence of clusters, and retrieve kubeconfig.
 type Provider interface {
 	// CreateCluster creates a new Kubernetes cluster with the given name.
-	CreateCluster(name string) error
+	// The given configuration is applied on top of the kind configuration file.
+	CreateCluster(ctx context.Context, name string, cfg ClusterConfig) error
 
 	// DeleteCluster deletes the Kubernetes cluster with the given name.
-	DeleteCluster(name string) error
+	DeleteCluster(ctx context.Context, name string) error
 
 	// ClusterExists checks if a Kubernetes cluster with the given name exists.
-	ClusterExists(name string) (bool, error)
+	ClusterExists(ctx context.Context, name string) (bool, error)
 
 	// KubeConfig retrieves the kubeconfig for the specified cluster name. The bool localhosts indicates whether the function returns a kubeconfig with the local host IP or the container IP.
-	KubeConfig(name string, localhost bool) (string, error)
+	KubeConfig(ctx context.Context, name string, localhost bool) (string, error)
+
+	// LoadImage loads an image of the local Docker daemon into all nodes of the cluster, like `kind load docker-image`.
+	// The image is pulled if it does not exist locally.
+	LoadImage(ctx context.Context, name, image string) error
+
+	// LoadImageArchive loads the images of an archive into all nodes of the cluster, like `kind load image-archive`.
+	LoadImageArchive(ctx context.Context, name, file string) error
+
+	// ClusterInfo returns the description of the node containers of the cluster.
+	ClusterInfo(ctx context.Context, name string) (ClusterInfo, error)
+
+	// ConfigHash returns the hash of the kind configuration a cluster with the given configuration is created with.
+	ConfigHash(ctx context.Context, cfg ClusterConfig) (string, error)
 }
 
 var (
 	kubeconfigPath = path.Join(os.TempDir(), "cluster-provider-kind.kubeconfig")
 )
 
+const (
+	// waitForReadyTimeout is how long CreateCluster waits for the control plane nodes to become ready.
+	waitForReadyTimeout = 1 * time.Minute
+	// waitForReadyInterval is how often CreateCluster checks whether the control plane nodes are ready.
+	waitForReadyInterval = 2 * time.Second
+)
+
 // NewKindProvider returns a new instance of the kind provider for managing Kubernetes clusters.
 // It uses the default Docker-based kind provider configuration.
 func NewKindProvider(configFile string) Provider {
@@ -51,8 +78,15 @@ type kindProvider struct {
 }
 
 // ClusterExists implements Provider.
-func (p *kindProvider) ClusterExists(name string) (bool, error) {
-	clusters, err := p.internal.List()
+func (p *kindProvider) ClusterExists(ctx context.Context, name string) (exists bool, err error) {
+	_, span := tracing.Start(ctx, "kind.ClusterExists", attribute.String(attrCluster, name))
+	defer func() { tracing.End(span, err) }()
+
+	var clusters []string
+	err = withEnv(dockerEndpointFrom(ctx).env(), func() (err error) {
+		clusters, err = p.internal.List()
+		return err
+	})
 	if err != nil {
 		return false, err
 	}
@@ -61,32 +95,77 @@ func (p *kindProvider) ClusterExists(name string) (bool, error) {
 }
 
 // CreateCluster implements Provider.
-func (p *kindProvider) CreateCluster(name string) error {
-	options := []cluster.CreateOption{
-		cluster.CreateWithWaitForReady(1 * time.Minute),
-		cluster.CreateWithKubeconfigPath(kubeconfigPath),
+func (p *kindProvider) CreateCluster(ctx context.Context, name string, cfg ClusterConfig) (err error) {
+	ctx, span := tracing.Start(ctx, "kind.CreateCluster", attribute.String(attrCluster, name), attribute.String(attrNetwork, cfg.Network))
+	defer func() { tracing.End(span, err) }()
+
+	kindCfg, err := loadKindConfig(p.configFile)
+	if err != nil {
+		return err
+	}
+	applyClusterConfig(kindCfg, cfg)
+
+	env := cfg.Proxy.env()
+	if cfg.Network != "" && cfg.Network != DefaultNetworkName {
+		env[envDockerNetwork] = cfg.Network
+	}
+	maps.Copy(env, dockerEndpointFrom(ctx).env())
+
+	// kind reads the settings from the environment of the process, so creations with settings are serialized with all
+	// other kind operations. The lock is only held while kind creates the cluster; it is released before waiting for the
+	// nodes to become ready, which does not depend on the environment.
+	err = withEnv(env, func() error {
+		return p.internal.Create(name,
+			cluster.CreateWithWaitForReady(0),
+			cluster.CreateWithKubeconfigPath(kubeconfigPath),
+			cluster.CreateWithV1Alpha4Config(kindCfg),
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Without a network plugin, the nodes do not become ready before it has been installed.
+	if !cfg.DisableDefaultCNI {
+		if err := p.waitForControlPlaneReady(ctx, name, waitForReadyTimeout); err != nil {
+			return err
+		}
 	}
-	if p.configFile != "" {
-		options = append(options, cluster.CreateWithConfigFile(p.configFile))
+
+	// Like kind does for failed creations, the cluster is deleted so that the next attempt starts from scratch.
+	if err := configureNodes(ctx, name, cfg); err != nil {
+		return errors.Join(err, p.DeleteCluster(ctx, name))
 	}
-	return p.internal.Create(name, options...)
+	return nil
 }
 
 // DeleteCluster implements Provider.
-func (p *kindProvider) DeleteCluster(name string) error {
-	return p.internal.Delete(name, kubeconfigPath)
+func (p *kindProvider) DeleteCluster(ctx context.Context, name string) (err error) {
+	_, span := tracing.Start(ctx, "kind.DeleteCluster", attribute.String(attrCluster, name))
+	defer func() { tracing.End(span, err) }()
+
+	return withEnv(dockerEndpointFrom(ctx).env(), func() error {
+		return p.internal.Delete(name, kubeconfigPath)
+	})
 }
 
 // KubeConfig implements Provider.
-func (p *kindProvider) KubeConfig(name string, localhost bool) (string, error) {
-	kubeconfigStr, err := p.internal.KubeConfig(name, !localhost)
+func (p *kindProvider) KubeConfig(ctx context.Context, name string, localhost bool) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "kind.KubeConfig", attribute.String(attrCluster, name), attribute.Bool("kind.localhost", localhost))
+	defer func() { tracing.End(span, err) }()
+
+	var kubeconfigStr string
+	err = withEnv(dockerEndpointFrom(ctx).env(), func() (err error) {
+		kubeconfigStr, err = p.internal.KubeConfig(name, !localhost)
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
 
 	containerName := p.controlPlaneContainer(name)
 
-	containerIP, err := getDockerContainerIP(containerName)
+	containerIP, err := getDockerContainerIP(ctx, containerName)
 	if err != nil {
 		return "", err
 	}
@@ -94,6 +173,45 @@ func (p *kindProvider) KubeConfig(name string, localhost bool) (string, error) {
 	return strings.ReplaceAll(kubeconfigStr, "https://"+containerName, "https://"+containerIP.String()), nil
 }
 
+// waitForControlPlaneReady waits until the control plane nodes of the cluster are ready, like kind does when it creates
+// a cluster with --wait. Like kind, it does not fail if the nodes are not ready before the timeout.
+func (p *kindProvider) waitForControlPlaneReady(ctx context.Context, name string, timeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitForReadyInterval)
+	defer ticker.Stop()
+	for {
+		cmd := docker(waitCtx, "exec", p.controlPlaneContainer(name),
+			"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "get", "nodes",
+			"--selector=node-role.kubernetes.io/control-plane", "-o=jsonpath={.items..status.conditions[-1:].status}")
+		if out, err := cmd.Output(); err == nil && nodesReady(out) {
+			return nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// nodesReady returns true if the output lists the status of the Ready condition of at least one node, and all of
+// them are True.
+func nodesReady(out []byte) bool {
+	statuses := strings.Fields(string(out))
+	if len(statuses) == 0 {
+		return false
+	}
+	for _, status := range statuses {
+		if status != "True" {
+			return false
+		}
+	}
+	return true
+}
+
 func (p *kindProvider) controlPlaneContainer(name string) string {
 	return fmt.Sprintf("%s-control-plane", name)
 }
@@ -0,0 +1,15 @@
+package kind
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_nodesReady(t *testing.T) {
+	assert.True(t, nodesReady([]byte("True")))
+	assert.True(t, nodesReady([]byte("True True True\n")))
+	assert.False(t, nodesReady([]byte("True False True")))
+	assert.False(t, nodesReady([]byte("Unknown")))
+	assert.False(t, nodesReady([]byte("")))
+}
@@ -0,0 +1,154 @@
+package kind
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultRegistryImage is the image used for the local registry container.
+	DefaultRegistryImage = "registry:2"
+	// DefaultRegistryHostPort is the port on the host the local registry is published on.
+	DefaultRegistryHostPort = 5001
+	// RegistryName is the name of the local registry container.
+	RegistryName = "kind-registry"
+
+	// RegistryContainerdConfigPatch makes containerd read the registry host configurations from the certs.d directory.
+	RegistryContainerdConfigPatch = `[plugins."io.containerd.grpc.v1.cri".registry]
+  config_path = "` + registryCertsDir + `"`
+
+	registryContainerPort  = 5000
+	registryVolume         = "kind-registry-data"
+	registryCertsDir       = "/etc/containerd/certs.d"
+	labelRegistryConfigSum = "kind.clusters.openmcp.cloud/registry-config-hash"
+	labelKindCluster       = "io.x-k8s.kind.cluster"
+)
+
+// RegistryConfig configures the local registry container that is shared by all kind clusters.
+type RegistryConfig struct {
+	// Image is the registry image. Defaults to DefaultRegistryImage.
+	Image string
+	// HostPort is the port on the host the registry is published on. Defaults to DefaultRegistryHostPort.
+	HostPort int32
+}
+
+// Host returns the address under which the registry is reachable from the host, e.g. to push images.
+// Images referencing this address are pulled from the registry by the kind nodes.
+func (cfg RegistryConfig) Host() string {
+	return "localhost:" + strconv.Itoa(int(cfg.hostPort()))
+}
+
+// Endpoint returns the address under which the registry is reachable from the kind network.
+func (cfg RegistryConfig) Endpoint() string {
+	return fmt.Sprintf("%s:%d", RegistryName, registryContainerPort)
+}
+
+func (cfg RegistryConfig) image() string {
+	if cfg.Image == "" {
+		return DefaultRegistryImage
+	}
+	return cfg.Image
+}
+
+func (cfg RegistryConfig) hostPort() int32 {
+	if cfg.HostPort == 0 {
+		return DefaultRegistryHostPort
+	}
+	return cfg.HostPort
+}
+
+// EnsureRegistry makes sure the local registry container is running in the kind network with the given configuration.
+// If the container exists with a different configuration, it is recreated. The images are kept in a volume.
+func EnsureRegistry(ctx context.Context, cfg RegistryConfig) error {
+	configSum := sha256.Sum256([]byte(cfg.image() + cfg.Host()))
+	configHash := hex.EncodeToString(configSum[:])[:16]
+
+	currentHash, err := getDockerContainerLabel(ctx, RegistryName, labelRegistryConfigSum)
+	switch {
+	case errors.Is(err, errContainerNotFound):
+		return runRegistry(ctx, cfg, configHash)
+	case err != nil:
+		return err
+	case currentHash != configHash:
+		if err := DeleteRegistry(ctx); err != nil {
+			return err
+		}
+		return runRegistry(ctx, cfg, configHash)
+	}
+
+	cmd := docker(ctx, "container", "start", RegistryName)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to start registry container: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// DeleteRegistry removes the local registry container. The volume with the images is kept.
+func DeleteRegistry(ctx context.Context) error {
+	cmd := docker(ctx, "container", "rm", "--force", RegistryName)
+	if out, err := cmd.CombinedOutput(); err != nil && !isNoSuchContainer(out) {
+		return fmt.Errorf("failed to remove registry container: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ConnectRegistry connects the local registry container to the given network, so that isolated clusters can pull from it.
+func ConnectRegistry(ctx context.Context, network string) error {
+	if network == DefaultNetworkName {
+		return nil
+	}
+	return connectNetwork(ctx, RegistryName, network)
+}
+
+// ConfigureRegistryMirror configures containerd on all nodes of the given kind cluster to pull images of the given host
+// from the given endpoint. It requires the kind cluster to be created with RegistryContainerdConfigPatch.
+func ConfigureRegistryMirror(ctx context.Context, clusterName, host, endpoint string) error {
+	nodes, err := clusterNodes(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		if err := writeHostsTOML(ctx, node, host, []string{"http://" + endpoint}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runRegistry(ctx context.Context, cfg RegistryConfig, configHash string) error {
+	args := []string{
+		"run", "--detach",
+		"--name", RegistryName,
+		"--network", DefaultNetworkName,
+		"--restart", "unless-stopped",
+		"--publish", fmt.Sprintf("127.0.0.1:%d:%d", cfg.hostPort(), registryContainerPort),
+		"--volume", registryVolume + ":/var/lib/registry",
+		"--label", labelManagedBy + "=" + labelManagedByValue,
+		"--label", labelRegistryConfigSum + "=" + configHash,
+		cfg.image(),
+	}
+
+	cmd := docker(ctx, args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to start registry container: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// clusterNodes returns the names of the node containers of the given kind cluster.
+func clusterNodes(ctx context.Context, clusterName string) ([]string, error) {
+	cmd := docker(ctx, "container", "ls", "--filter", "label="+labelKindCluster+"="+clusterName, "--format", "{{.Names}}")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes of cluster %s: %w", clusterName, err)
+	}
+
+	return strings.Fields(string(bytes.TrimSpace(out))), nil
+}
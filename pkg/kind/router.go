@@ -0,0 +1,220 @@
+package kind
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"text/template"
+)
+
+const (
+	// DefaultRouterImage is the FRR image used for BGP router containers.
+	DefaultRouterImage = "quay.io/frrouting/frr:9.1.0"
+
+	labelManagedBy       = "app.kubernetes.io/managed-by"
+	labelManagedByValue  = "cluster-provider-kind"
+	labelRouterConfigSum = "kind.clusters.openmcp.cloud/router-config-hash"
+
+	bgpStateEstablished = "Established"
+)
+
+var (
+	errContainerNotFound = errors.New("container not found")
+
+	frrConfigTemplate = template.Must(template.New("frr.conf").Parse(`frr defaults traditional
+hostname {{ .Name }}
+log stdout
+!
+router bgp {{ .ASN }}
+ no bgp ebgp-requires-policy
+ no bgp default ipv4-unicast
+ neighbor metallb peer-group
+ neighbor metallb remote-as {{ .PeerASN }}
+ bgp listen range {{ .ListenRange }} peer-group metallb
+ !
+ address-family ipv4 unicast
+  neighbor metallb activate
+ exit-address-family
+!
+`))
+
+	// routerEntrypoint writes the FRR configuration passed via environment, enables bgpd and starts FRR.
+	routerEntrypoint = `printf '%s\n' "$FRR_CONFIG" > /etc/frr/frr.conf && ` +
+		`sed -i 's/^bgpd=no/bgpd=yes/' /etc/frr/daemons && ` +
+		`exec /usr/lib/frr/docker-start`
+)
+
+// RouterConfig configures an FRR router container that runs in the kind network and peers with MetalLB via BGP.
+type RouterConfig struct {
+	// Name is the name of the router container.
+	Name string
+	// Image is the FRR image. Defaults to DefaultRouterImage.
+	Image string
+	// ASN is the autonomous system number of the router.
+	ASN int64
+	// PeerASN is the autonomous system number MetalLB uses.
+	PeerASN int64
+	// ListenRange is the network from which BGP sessions are accepted.
+	ListenRange net.IPNet
+	// Network is the Docker network the router runs in. Defaults to DefaultNetworkName.
+	Network string
+}
+
+// EnsureRouter makes sure the router container is running with the given configuration and returns its IP address.
+// If the container exists with a different configuration, it is recreated.
+func EnsureRouter(ctx context.Context, cfg RouterConfig) (net.IP, error) {
+	if cfg.Image == "" {
+		cfg.Image = DefaultRouterImage
+	}
+	if cfg.Network == "" {
+		cfg.Network = DefaultNetworkName
+	}
+
+	frrConfig, err := renderRouterConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	configSum := sha256.Sum256([]byte(cfg.Image + frrConfig))
+	configHash := hex.EncodeToString(configSum[:])[:16]
+
+	currentHash, err := getDockerContainerLabel(ctx, cfg.Name, labelRouterConfigSum)
+	switch {
+	case errors.Is(err, errContainerNotFound):
+		if err := runRouter(ctx, cfg, frrConfig, configHash); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case currentHash != configHash:
+		if err := DeleteRouter(ctx, cfg.Name); err != nil {
+			return nil, err
+		}
+		if err := runRouter(ctx, cfg, frrConfig, configHash); err != nil {
+			return nil, err
+		}
+	}
+
+	return getDockerContainerIP(ctx, cfg.Name)
+}
+
+// DeleteRouter removes the router container with the given name. It does not fail if the container does not exist.
+func DeleteRouter(ctx context.Context, name string) error {
+	cmd := docker(ctx, "container", "rm", "--force", name)
+	if out, err := cmd.CombinedOutput(); err != nil && !isNoSuchContainer(out) {
+		return fmt.Errorf("failed to remove router container %s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// EstablishedRouterPeers returns the addresses of all BGP peers the router has an established session with.
+func EstablishedRouterPeers(ctx context.Context, name string) ([]net.IP, error) {
+	cmd := docker(ctx, "exec", name, "vtysh", "-c", "show bgp summary json")
+	cmdOut, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query BGP summary of router %s: %w", name, err)
+	}
+
+	return parseEstablishedPeers(cmdOut)
+}
+
+func runRouter(ctx context.Context, cfg RouterConfig, frrConfig, configHash string) error {
+	args := []string{
+		"run", "--detach",
+		"--name", cfg.Name,
+		"--network", cfg.Network,
+		"--restart", "unless-stopped",
+		"--cap-add", "NET_ADMIN",
+		"--cap-add", "NET_RAW",
+		"--cap-add", "SYS_ADMIN",
+		"--label", labelManagedBy + "=" + labelManagedByValue,
+		"--label", labelRouterConfigSum + "=" + configHash,
+		"--env", "FRR_CONFIG=" + frrConfig,
+		"--entrypoint", "/bin/sh",
+		cfg.Image,
+		"-c", routerEntrypoint,
+	}
+
+	cmd := docker(ctx, args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to start router container %s: %w: %s", cfg.Name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func renderRouterConfig(cfg RouterConfig) (string, error) {
+	buf := &bytes.Buffer{}
+	err := frrConfigTemplate.Execute(buf, map[string]any{
+		"Name":        cfg.Name,
+		"ASN":         cfg.ASN,
+		"PeerASN":     cfg.PeerASN,
+		"ListenRange": cfg.ListenRange.String(),
+	})
+	return buf.String(), err
+}
+
+// bgpSummary is the relevant part of the output of `vtysh -c "show bgp summary json"`, keyed by address family.
+type bgpSummary map[string]struct {
+	Peers map[string]struct {
+		State string `json:"state"`
+	} `json:"peers"`
+}
+
+func parseEstablishedPeers(cmdOut []byte) ([]net.IP, error) {
+	summary := bgpSummary{}
+	if err := json.Unmarshal(cmdOut, &summary); err != nil {
+		return nil, err
+	}
+
+	peers := []net.IP{}
+	for _, af := range summary {
+		for addr, peer := range af.Peers {
+			if peer.State != bgpStateEstablished {
+				continue
+			}
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				return nil, errInvalidIP
+			}
+			peers = append(peers, ip)
+		}
+	}
+	return peers, nil
+}
+
+func getDockerContainerLabel(ctx context.Context, containerName, label string) (string, error) {
+	cmd := docker(ctx, "container", "inspect", "-f", fmt.Sprintf("{{index .Config.Labels %q}}", label), containerName)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if isNoSuchContainer(out) {
+			return "", errContainerNotFound
+		}
+		return "", fmt.Errorf("failed to inspect container %s: %w: %s", containerName, err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func getDockerContainerLabels(ctx context.Context, containerName string) (map[string]string, error) {
+	cmd := docker(ctx, "container", "inspect", "-f", "{{json .Config.Labels}}", containerName)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if isNoSuchContainer(out) {
+			return nil, errContainerNotFound
+		}
+		return nil, fmt.Errorf("failed to inspect container %s: %w: %s", containerName, err, strings.TrimSpace(string(out)))
+	}
+	labels := map[string]string{}
+	if err := json.Unmarshal(out, &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse labels of container %s: %w", containerName, err)
+	}
+	return labels, nil
+}
+
+func isNoSuchContainer(out []byte) bool {
+	return bytes.Contains(bytes.ToLower(out), []byte("no such container"))
+}
@@ -0,0 +1,58 @@
+package kind
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_renderRouterConfig(t *testing.T) {
+	cfg, err := renderRouterConfig(RouterConfig{
+		Name:        "kind-frr",
+		ASN:         64512,
+		PeerASN:     64513,
+		ListenRange: mustParseCIDR("172.19.0.0/16"),
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, cfg, "hostname kind-frr\n")
+	assert.Contains(t, cfg, "router bgp 64512\n")
+	assert.Contains(t, cfg, " neighbor metallb remote-as 64513\n")
+	assert.Contains(t, cfg, " bgp listen range 172.19.0.0/16 peer-group metallb\n")
+}
+
+func Test_parseEstablishedPeers(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		jsonData      string
+		expectedPeers []net.IP
+		expectErr     bool
+	}{
+		{
+			desc:          "should return established peers only",
+			jsonData:      `{"ipv4Unicast":{"routerId":"172.19.0.6","as":64512,"peers":{"172.19.0.2":{"remoteAs":64513,"state":"Established","dynamicPeer":true},"172.19.0.3":{"remoteAs":64513,"state":"Active","dynamicPeer":true}}}}`,
+			expectedPeers: []net.IP{net.ParseIP("172.19.0.2")},
+		},
+		{
+			desc:          "should return no peers if there are no sessions",
+			jsonData:      `{}`,
+			expectedPeers: []net.IP{},
+		},
+		{
+			desc:      "should fail for invalid json",
+			jsonData:  `% BGP instance not found`,
+			expectErr: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			peers, err := parseEstablishedPeers([]byte(tC.jsonData))
+			if tC.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.expectedPeers, peers)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+)
+
+// ErrNotEmbedded is returned if a manifest has not been generated before the provider was built.
+var ErrNotEmbedded = errors.New("manifest is not embedded")
+
+const resourceKustomization = "kustomization.yaml"
+
+// Source reads the manifests of a component from a directory of a file system,
+// usually the manifests that are downloaded by go:generate and embedded into the provider.
+type Source struct {
+	// FS is the file system the manifests are read from.
+	FS fs.FS
+	// Dir is the directory of the manifests in FS.
+	Dir string
+	// Package is the package whose go:generate directives download the manifests, e.g. "./pkg/cni/...".
+	Package string
+}
+
+// Read returns the content of the given manifest file.
+// It returns ErrNotEmbedded if the file has not been generated.
+func (s *Source) Read(file string) ([]byte, error) {
+	data, err := fs.ReadFile(s.FS, path.Join(s.Dir, file))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s is missing, run go generate %s", ErrNotEmbedded, file, s.Package)
+	}
+	return data, err
+}
+
+// ReadDir returns the entries of the given directory of the source.
+func (s *Source) ReadDir(dir string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(s.FS, path.Join(s.Dir, dir))
+}
+
+// RenderOptions configures how a manifest is rendered.
+type RenderOptions struct {
+	// PartOf is the value of the LabelPartOf label that is set on all objects, together with LabelManagedBy.
+	PartOf string
+	// Namespace overrides the namespace of all namespaced objects, if set.
+	Namespace string
+	// Images overrides the images referenced in the manifest.
+	Images []types.Image
+	// Patches are applied to the objects of the manifest.
+	Patches []types.Patch
+}
+
+// Render renders the given manifest file of the source with kustomize.
+func (s *Source) Render(file string, opts RenderOptions) ([]*unstructured.Unstructured, error) {
+	data, err := s.Read(file)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := path.Base(file)
+	k := &types.Kustomization{
+		TypeMeta: types.TypeMeta{
+			Kind:       types.KustomizationKind,
+			APIVersion: types.KustomizationVersion,
+		},
+		Namespace: opts.Namespace,
+		Resources: []string{
+			resource,
+		},
+		Images:  opts.Images,
+		Patches: opts.Patches,
+		Labels: []types.Label{
+			{
+				Pairs: map[string]string{
+					LabelManagedBy: LabelManagedByValue,
+					LabelPartOf:    opts.PartOf,
+				},
+			},
+		},
+	}
+
+	jsonBytes, err := json.Marshal(k)
+	if err != nil {
+		return nil, err
+	}
+
+	memFS := filesys.MakeFsInMemory()
+	err = errors.Join(
+		memFS.WriteFile(resource, data),
+		memFS.WriteFile(resourceKustomization, jsonBytes),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return Render(memFS, ".")
+}
+
+// Images converts the image overrides of the ProviderConfig into kustomize images.
+func Images(overrides []v1alpha1.ImageOverride) []types.Image {
+	images := make([]types.Image, 0, len(overrides))
+	for _, img := range overrides {
+		images = append(images, types.Image{
+			Name:    img.Name,
+			NewName: img.NewName,
+			NewTag:  img.NewTag,
+			Digest:  img.Digest,
+		})
+	}
+	return images
+}
@@ -0,0 +1,75 @@
+package manifest
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+)
+
+const testDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller
+spec:
+  template:
+    spec:
+      containers:
+      - name: controller
+        image: registry.example/controller:v1.0.0
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: controller
+`
+
+func TestSource_Render(t *testing.T) {
+	source := &Source{
+		FS:      fstest.MapFS{"manifests/component/install.yaml": {Data: []byte(testDeployment)}},
+		Dir:     "manifests",
+		Package: "./pkg/component/...",
+	}
+
+	objs, err := source.Render("component/install.yaml", RenderOptions{
+		PartOf:    "component",
+		Namespace: "component-system",
+		Images:    Images([]v1alpha1.ImageOverride{{Name: "registry.example/controller", NewName: "registry.local/controller"}}),
+	})
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, obj := range objs {
+		names = append(names, ObjectName(obj))
+		assert.Equal(t, LabelManagedByValue, obj.GetLabels()[LabelManagedBy])
+		assert.Equal(t, "component", obj.GetLabels()[LabelPartOf])
+	}
+	assert.ElementsMatch(t, []string{"Deployment/component-system/controller", "ClusterRole/controller"}, names)
+
+	containers, _, _ := unstructured.NestedSlice(objs[0].Object, "spec", "template", "spec", "containers")
+	assert.Equal(t, "registry.local/controller:v1.0.0", containers[0].(map[string]any)["image"])
+}
+
+func TestSource_Read_notEmbedded(t *testing.T) {
+	source := &Source{FS: fstest.MapFS{}, Dir: "manifests", Package: "./pkg/component/..."}
+
+	_, err := source.Read("component/install.yaml")
+	assert.ErrorIs(t, err, ErrNotEmbedded)
+	assert.ErrorContains(t, err, "component/install.yaml is missing, run go generate ./pkg/component/...")
+}
+
+func TestImages(t *testing.T) {
+	images := Images([]v1alpha1.ImageOverride{
+		{Name: "a", NewName: "registry.local/a"},
+		{Name: "b", NewTag: "v2", Digest: "sha256:0123"},
+	})
+	assert.Equal(t, []types.Image{
+		{Name: "a", NewName: "registry.local/a"},
+		{Name: "b", NewTag: "v2", Digest: "sha256:0123"},
+	}, images)
+}
@@ -0,0 +1,174 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+const (
+	// FieldManager is the field manager used to server-side apply manifests.
+	FieldManager = "cluster-provider-kind"
+
+	// LabelManagedBy is set on all objects applied by the provider.
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	// LabelManagedByValue is the value of LabelManagedBy.
+	LabelManagedByValue = "cluster-provider-kind"
+	// LabelPartOf identifies the component an object applied by the provider belongs to.
+	LabelPartOf = "app.kubernetes.io/part-of"
+)
+
+// Result describes the changes that have been made by Apply and Prune.
+type Result struct {
+	// Created contains the objects that have been created.
+	Created []string
+	// Updated contains the objects that have been changed.
+	Updated []string
+	// Pruned contains the objects that have been deleted because they are no longer part of the manifests.
+	Pruned []string
+}
+
+// HasChanges returns true if any object has been created, updated or pruned.
+func (r Result) HasChanges() bool {
+	return len(r.Created) > 0 || len(r.Updated) > 0 || len(r.Pruned) > 0
+}
+
+// String returns a human-readable summary of the changes.
+func (r Result) String() string {
+	return fmt.Sprintf("created: %v, updated: %v, pruned: %v", r.Created, r.Updated, r.Pruned)
+}
+
+// Render runs kustomize on the kustomization in the given directory of the file system
+// and returns the resulting objects.
+func Render(fs filesys.FileSystem, dir string) (objs []*unstructured.Unstructured, err error) {
+	// Recover if Kustomize panics
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("kustomize panic: %v", r)
+		}
+	}()
+
+	k := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	r, err := k.Run(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range r.Resources() {
+		yamlBytes, err := res.AsYAML()
+		if err != nil {
+			return nil, err
+		}
+
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(yamlBytes, u); err != nil {
+			return nil, err
+		}
+
+		objs = append(objs, u)
+	}
+
+	return objs, nil
+}
+
+// Apply server-side applies the given objects in order and records which objects have been created or changed.
+func Apply(ctx context.Context, c client.Client, objs []*unstructured.Unstructured, result *Result) error {
+	for _, obj := range objs {
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(obj.GroupVersionKind())
+		err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get object %s: %w", ObjectName(obj), err)
+		}
+		exists := err == nil
+
+		if err := c.Apply(ctx, client.ApplyConfigurationFromUnstructured(obj), client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+			return fmt.Errorf("failed to apply object %s: %w", ObjectName(obj), err)
+		}
+
+		switch {
+		case !exists:
+			result.Created = append(result.Created, ObjectName(obj))
+		case contentChanged(existing, obj):
+			result.Updated = append(result.Updated, ObjectName(obj))
+		}
+	}
+
+	return nil
+}
+
+// Prune deletes all objects of the given kinds that match the given labels, but are not part of the desired objects.
+// Kinds that are not known to the cluster are skipped.
+func Prune(ctx context.Context, c client.Client, desired []*unstructured.Unstructured, kinds []schema.GroupVersionKind, labels client.MatchingLabels, result *Result) error {
+	keep := map[string]bool{}
+	for _, obj := range desired {
+		keep[ObjectName(obj)] = true
+	}
+
+	for _, gvk := range kinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list, labels); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+		}
+
+		for _, item := range list.Items {
+			if keep[ObjectName(&item)] {
+				continue
+			}
+			if err := c.Delete(ctx, &item); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to prune object %s: %w", ObjectName(&item), err)
+			}
+			result.Pruned = append(result.Pruned, ObjectName(&item))
+		}
+	}
+
+	return nil
+}
+
+// Kinds returns the distinct kinds of the given objects.
+func Kinds(objs []*unstructured.Unstructured) []schema.GroupVersionKind {
+	kinds := []schema.GroupVersionKind{}
+	seen := map[schema.GroupVersionKind]bool{}
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		if !seen[gvk] {
+			seen[gvk] = true
+			kinds = append(kinds, gvk)
+		}
+	}
+	return kinds
+}
+
+// ObjectName returns a human-readable identifier of the object in the form "Kind/namespace/name".
+func ObjectName(obj client.Object) string {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", gvk.Kind, obj.GetName())
+	}
+	return fmt.Sprintf("%s/%s/%s", gvk.Kind, obj.GetNamespace(), obj.GetName())
+}
+
+// contentChanged compares two revisions of an object, ignoring the status and metadata that changes on every write.
+func contentChanged(before, after *unstructured.Unstructured) bool {
+	strip := func(u *unstructured.Unstructured) map[string]any {
+		c := u.DeepCopy()
+		c.SetResourceVersion("")
+		c.SetManagedFields(nil)
+		c.SetGeneration(0)
+		unstructured.RemoveNestedField(c.Object, "status")
+		return c.Object
+	}
+	return !equality.Semantic.DeepEqual(strip(before), strip(after))
+}
@@ -0,0 +1,17 @@
+// Package manifesttest provides helpers for testing components that render embedded manifests.
+package manifesttest
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+)
+
+// WithFS replaces the file system of the given source with the given files until the test has finished.
+func WithFS(t testing.TB, source *manifest.Source, files fs.FS) {
+	t.Helper()
+	original := source.FS
+	source.FS = files
+	t.Cleanup(func() { source.FS = original })
+}
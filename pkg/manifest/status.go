@@ -0,0 +1,89 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NotReady returns the names of the given objects that exist in the cluster, but are not ready yet,
+// and of the objects that do not exist.
+// Objects of kinds without a known readiness criterion are ready as soon as they exist.
+func NotReady(ctx context.Context, c client.Client, objs []*unstructured.Unstructured) ([]string, error) {
+	notReady := []string{}
+	for _, obj := range objs {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to get object %s: %w", ObjectName(obj), err)
+			}
+			notReady = append(notReady, ObjectName(obj))
+			continue
+		}
+
+		if !isReady(current) {
+			notReady = append(notReady, ObjectName(obj))
+		}
+	}
+	return notReady, nil
+}
+
+func isReady(obj *unstructured.Unstructured) bool {
+	gk := obj.GroupVersionKind().GroupKind()
+
+	switch gk {
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		return hasCondition(obj, "Established")
+	case schema.GroupKind{Kind: "Namespace"}:
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		return phase == "" || phase == "Active"
+	case schema.GroupKind{Group: "batch", Kind: "Job"}:
+		return hasCondition(obj, "Complete")
+	}
+
+	if !observedGenerationCurrent(obj) {
+		return false
+	}
+
+	switch gk {
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"}:
+		return hasCondition(obj, "Available") && replicasEqual(obj, "updatedReplicas")
+	case schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+		return replicasEqual(obj, "readyReplicas")
+	case schema.GroupKind{Group: "apps", Kind: "DaemonSet"}:
+		desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberReady")
+		return desired == ready
+	}
+
+	return true
+}
+
+// observedGenerationCurrent returns false if the controller has not observed the latest generation of the object yet.
+func observedGenerationCurrent(obj *unstructured.Unstructured) bool {
+	observed, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	return !found || observed >= obj.GetGeneration()
+}
+
+// replicasEqual compares the desired number of replicas with the given replica count in the status.
+func replicasEqual(obj *unstructured.Unstructured, statusField string) bool {
+	desired, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		desired = 1
+	}
+	actual, _, _ := unstructured.NestedInt64(obj.Object, "status", statusField)
+	return desired == actual
+}
+
+func hasCondition(obj *unstructured.Unstructured, condType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	return slices.ContainsFunc(conditions, func(c any) bool {
+		m, ok := c.(map[string]any)
+		return ok && m["type"] == condType && m["status"] == "True"
+	})
+}
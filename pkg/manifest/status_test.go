@@ -0,0 +1,90 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_isReady(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		obj      map[string]any
+		expected bool
+	}{
+		{
+			desc: "should be ready for established CRD",
+			obj: map[string]any{
+				"apiVersion": "apiextensions.k8s.io/v1",
+				"kind":       "CustomResourceDefinition",
+				"status": map[string]any{
+					"conditions": []any{map[string]any{"type": "Established", "status": "True"}},
+				},
+			},
+			expected: true,
+		},
+		{
+			desc: "should not be ready for CRD that is not established",
+			obj: map[string]any{
+				"apiVersion": "apiextensions.k8s.io/v1",
+				"kind":       "CustomResourceDefinition",
+			},
+			expected: false,
+		},
+		{
+			desc: "should be ready for available deployment",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"generation": int64(2)},
+				"spec":       map[string]any{"replicas": int64(2)},
+				"status": map[string]any{
+					"observedGeneration": int64(2),
+					"updatedReplicas":    int64(2),
+					"conditions":         []any{map[string]any{"type": "Available", "status": "True"}},
+				},
+			},
+			expected: true,
+		},
+		{
+			desc: "should not be ready for deployment with outdated generation",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"generation": int64(3)},
+				"status": map[string]any{
+					"observedGeneration": int64(2),
+					"updatedReplicas":    int64(1),
+					"conditions":         []any{map[string]any{"type": "Available", "status": "True"}},
+				},
+			},
+			expected: false,
+		},
+		{
+			desc: "should not be ready for daemonset with missing pods",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "DaemonSet",
+				"status": map[string]any{
+					"desiredNumberScheduled": int64(3),
+					"numberReady":            int64(2),
+				},
+			},
+			expected: false,
+		},
+		{
+			desc: "should be ready for kinds without readiness criterion",
+			obj: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+			},
+			expected: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.expected, isReady(&unstructured.Unstructured{Object: tC.obj}))
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package metallb
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/metrics"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/tracing"
+)
+
+const (
+	// AddonName is the name of the MetalLB addon.
+	AddonName = "MetalLB"
+
+	// ConditionBGPPeeringReady reports whether all nodes have an established BGP session with the router.
+	ConditionBGPPeeringReady = "BGPPeeringReady"
+
+	defaultRouterASN  = 64512
+	defaultClusterASN = 64513
+)
+
+var (
+	errNoSubnet = errors.New("no subnet assigned to cluster")
+)
+
+// NewAddon returns the addon that installs MetalLB and configures the subnet assigned to the cluster.
+func NewAddon() addon.Addon {
+	return &metallbAddon{}
+}
+
+var _ addon.Addon = &metallbAddon{}
+var _ addon.Finalizer = &metallbAddon{}
+
+type metallbAddon struct{}
+
+// Name implements addon.Addon.
+func (a *metallbAddon) Name() string {
+	return AddonName
+}
+
+// Install implements addon.Addon.
+func (a *metallbAddon) Install(ctx context.Context, ac addon.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "metallb.Install", attribute.String("kind.cluster", ac.KindName))
+	defer func() { tracing.End(span, err) }()
+
+	opts, err := options(ac.Config)
+	if err != nil {
+		return err
+	}
+
+	result, err := Install(ctx, ac.Client, opts)
+	if result.HasChanges() {
+		ac.Recorder.Eventf(ac.Cluster, nil, corev1.EventTypeNormal, "MetalLBApplied", "ApplyMetalLB", "Applied MetalLB %s: %s", opts.Version, result)
+	}
+	if err != nil {
+		metrics.MetalLBInstallFailures.Inc()
+		return err
+	}
+
+	ac.ProviderStatus.MetalLBVersion = opts.Version
+	return nil
+}
+
+// Ready implements addon.Addon.
+func (a *metallbAddon) Ready(ctx context.Context, ac addon.Context) (ready bool, err error) {
+	ctx, span := tracing.Start(ctx, "metallb.Ready", attribute.String("kind.cluster", ac.KindName))
+	defer func() {
+		span.SetAttributes(attribute.Bool("metallb.ready", ready))
+		tracing.End(span, err)
+	}()
+
+	return IsReady(ctx, ac.Client)
+}
+
+// Configure implements addon.Addon.
+func (a *metallbAddon) Configure(ctx context.Context, ac addon.Context) error {
+	subnet, err := kind.SubnetFromCluster(ac.Cluster)
+	if err != nil {
+		return err
+	}
+	if subnet == nil {
+		return errNoSubnet
+	}
+
+	advertisement, err := advertisementConfig(ctx, ac.Config, ac.KindName, kind.NetworkFromCluster(ac.Cluster))
+	if err != nil {
+		return err
+	}
+
+	if err := ConfigureSubnet(ctx, ac.Client, *subnet, advertisement); err != nil {
+		return err
+	}
+
+	if advertisement.BGP == nil {
+		meta.RemoveStatusCondition(&ac.Cluster.Status.Conditions, ConditionBGPPeeringReady)
+		return nil
+	}
+
+	established, err := checkBGPPeering(ctx, ac, routerName(ac.KindName, kind.NetworkFromCluster(ac.Cluster), bgpConfig(ac.Config).SharedRouter))
+	if err != nil {
+		return err
+	}
+	if !established {
+		return fmt.Errorf("%w: waiting for BGP sessions to be established", addon.ErrPending)
+	}
+	return nil
+}
+
+// Uninstall implements addon.Addon.
+func (a *metallbAddon) Uninstall(ctx context.Context, ac addon.Context) error {
+	if err := Uninstall(ctx, ac.Client); err != nil {
+		return err
+	}
+
+	meta.RemoveStatusCondition(&ac.Cluster.Status.Conditions, ConditionBGPPeeringReady)
+	ac.ProviderStatus.MetalLBVersion = ""
+	return kind.DeleteRouter(ctx, routerName(ac.KindName, "", false))
+}
+
+// Finalize implements addon.Finalizer. It removes the router of the cluster. A router shared by the clusters of a
+// network is removed by kind.DeleteNetwork once the last cluster of the network has been deleted.
+func (a *metallbAddon) Finalize(ctx context.Context, kindName string) error {
+	return kind.DeleteRouter(ctx, routerName(kindName, "", false))
+}
+
+// options translates the MetalLB configuration of the ProviderConfig into installation options.
+func options(pc *v1alpha1.ProviderConfig) (Options, error) {
+	cfg := ptr.Deref(pc.Spec.MetalLB, v1alpha1.MetalLBConfig{})
+
+	version, err := ResolveVersion(cfg.Version)
+	if err != nil {
+		return Options{}, err
+	}
+
+	return Options{
+		Version: version,
+		Images:  manifest.Images(cfg.Images),
+	}, nil
+}
+
+func bgpConfig(pc *v1alpha1.ProviderConfig) v1alpha1.BGPConfig {
+	cfg := ptr.Deref(pc.Spec.MetalLB, v1alpha1.MetalLBConfig{})
+	return ptr.Deref(cfg.BGP, v1alpha1.BGPConfig{})
+}
+
+// advertisementConfig returns how MetalLB advertises the LoadBalancer IPs.
+// In BGP mode, it makes sure the router container the MetalLB speakers peer with is running.
+func advertisementConfig(ctx context.Context, pc *v1alpha1.ProviderConfig, kindName, network string) (AdvertisementConfig, error) {
+	if pc.Spec.MetalLB == nil || pc.Spec.MetalLB.Mode != v1alpha1.MetalLBModeBGP {
+		return AdvertisementConfig{}, nil
+	}
+
+	bgp := bgpConfig(pc)
+	routerASN := cmp.Or(bgp.RouterASN, defaultRouterASN)
+	clusterASN := cmp.Or(bgp.ClusterASN, defaultClusterASN)
+
+	kindNetwork, err := kind.GetDockerV4Network(ctx, network)
+	if err != nil {
+		return AdvertisementConfig{}, err
+	}
+
+	routerIP, err := kind.EnsureRouter(ctx, kind.RouterConfig{
+		Name:        routerName(kindName, network, bgp.SharedRouter),
+		Image:       bgp.RouterImage,
+		ASN:         routerASN,
+		PeerASN:     clusterASN,
+		ListenRange: kindNetwork,
+		Network:     network,
+	})
+	if err != nil {
+		return AdvertisementConfig{}, err
+	}
+
+	return AdvertisementConfig{
+		BGP: &BGPConfig{
+			PeerAddress: routerIP,
+			PeerASN:     routerASN,
+			MyASN:       clusterASN,
+		},
+	}, nil
+}
+
+// checkBGPPeering checks whether every node of the kind cluster has an established BGP session with the router
+// and reports the result as condition.
+func checkBGPPeering(ctx context.Context, ac addon.Context, router string) (bool, error) {
+	nodes := &corev1.NodeList{}
+	if err := ac.Client.List(ctx, nodes); err != nil {
+		return false, err
+	}
+
+	peers, err := kind.EstablishedRouterPeers(ctx, router)
+	if err != nil {
+		return false, err
+	}
+
+	established := 0
+	for _, node := range nodes.Items {
+		if slices.ContainsFunc(peers, func(peer net.IP) bool { return nodeHasAddress(&node, peer) }) {
+			established++
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:    ConditionBGPPeeringReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AllPeersEstablished",
+		Message: fmt.Sprintf("%d of %d nodes peer with router %s", established, len(nodes.Items), router),
+	}
+	if established < len(nodes.Items) {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PeersNotEstablished"
+	}
+	meta.SetStatusCondition(&ac.Cluster.Status.Conditions, condition)
+
+	return condition.Status == metav1.ConditionTrue, nil
+}
+
+func nodeHasAddress(node *corev1.Node, ip net.IP) bool {
+	return slices.ContainsFunc(node.Status.Addresses, func(addr corev1.NodeAddress) bool {
+		return addr.Type == corev1.NodeInternalIP && ip.Equal(net.ParseIP(addr.Address))
+	})
+}
+
+// routerName returns the name of the router container for the given kind cluster.
+// A shared router is shared by all clusters in the same Docker network.
+func routerName(kindName, network string, shared bool) string {
+	if shared {
+		return network + "-frr"
+	}
+	return kindName + "-frr"
+}
@@ -1,74 +1,222 @@
 package metallb
 
 import (
+	"bytes"
 	"context"
-	_ "embed"
-	"encoding/json"
+	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"path"
+	"slices"
+	"sync"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-	"sigs.k8s.io/kustomize/api/krusty"
-	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/types"
-	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
 )
 
-//go:generate curl -L -o metallb-native.yaml https://raw.githubusercontent.com/metallb/metallb/v0.14.9/config/manifests/metallb-native.yaml
+//go:generate curl -L --create-dirs -o manifests/v0.14.9/metallb-native.yaml https://raw.githubusercontent.com/metallb/metallb/v0.14.9/config/manifests/metallb-native.yaml
+//go:generate curl -L --create-dirs -o manifests/v0.15.2/metallb-native.yaml https://raw.githubusercontent.com/metallb/metallb/v0.15.2/config/manifests/metallb-native.yaml
 
 var (
-	//go:embed metallb-native.yaml
-	metallbYAML []byte
+	//go:embed manifests
+	embedded embed.FS
+
+	manifests = &manifest.Source{FS: embedded, Dir: "manifests", Package: "./pkg/metallb/..."}
+
+	// ErrUnknownVersion is returned if the requested MetalLB version is not embedded.
+	ErrUnknownVersion = errors.New("unknown MetalLB version")
+
+	ipAddressPoolGVK    = schema.GroupVersionKind{Group: "metallb.io", Version: "v1beta1", Kind: "IPAddressPool"}
+	l2AdvertisementGVK  = schema.GroupVersionKind{Group: "metallb.io", Version: "v1beta1", Kind: "L2Advertisement"}
+	bgpAdvertisementGVK = schema.GroupVersionKind{Group: "metallb.io", Version: "v1beta1", Kind: "BGPAdvertisement"}
+	bgpPeerGVK          = schema.GroupVersionKind{Group: "metallb.io", Version: "v1beta2", Kind: "BGPPeer"}
+
+	pruneLabels = client.MatchingLabels{manifest.LabelManagedBy: manifest.LabelManagedByValue, manifest.LabelPartOf: labelPartOfValue}
 )
 
 const (
+	// DefaultVersion is the MetalLB version that is installed if no version is specified.
+	DefaultVersion = "v0.14.9"
+
 	namespace = "metallb-system"
 
-	resourceBaseYAML      = "metallb-native.yaml"
-	resourceKustomization = "kustomization.yaml"
+	labelPartOfValue = "metallb"
+
+	// objectNameKind is the name of the MetalLB configuration objects created by the provider.
+	objectNameKind = "kind"
+
+	resourceBaseYAML = "metallb-native.yaml"
 )
 
-// Install installs the MetalLB components in the cluster.
-func Install(ctx context.Context, c client.Client) error {
-	r, err := build()
+// Options configures the MetalLB installation.
+type Options struct {
+	// Version is the MetalLB version to install. Defaults to DefaultVersion.
+	Version string
+	// Images overrides the images referenced in the MetalLB manifests.
+	Images []types.Image
+}
+
+// Versions returns the MetalLB versions that are embedded and can be installed.
+func Versions() []string {
+	entries, err := manifests.ReadDir(".")
+	if err != nil {
+		return nil
+	}
+
+	versions := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			versions = append(versions, e.Name())
+		}
+	}
+	return versions
+}
+
+// ResolveVersion returns the MetalLB version that is installed for the given requested version.
+func ResolveVersion(version string) (string, error) {
+	if version == "" {
+		return DefaultVersion, nil
+	}
+	if !slices.Contains(Versions(), version) {
+		return "", fmt.Errorf("%w %q, supported versions are %v", ErrUnknownVersion, version, Versions())
+	}
+	return version, nil
+}
+
+// Install installs or upgrades the MetalLB components in the cluster.
+// The manifests are server-side applied and objects that are no longer part of the manifests are pruned.
+func Install(ctx context.Context, c client.Client, opts Options) (manifest.Result, error) {
+	objs, err := build(opts)
+	if err != nil {
+		return manifest.Result{}, err
+	}
+
+	result := manifest.Result{}
+	if err := manifest.Apply(ctx, c, objs, &result); err != nil {
+		return result, err
+	}
+
+	embedded, err := loadEmbeddedObjects()
+	if err != nil {
+		return result, err
+	}
+	if err := adoptLegacyObjects(ctx, c, embedded); err != nil {
+		return result, err
+	}
+
+	return result, manifest.Prune(ctx, c, objs, embedded.kinds, pruneLabels, &result)
+}
+
+// adoptLegacyObjects adds the part-of label to the MetalLB objects created by earlier versions of the provider, which
+// only set the managed-by label, so that they are pruned like the objects applied since. Objects of the provider
+// without part-of label are MetalLB objects if they are in the MetalLB namespace or if a cluster-scoped object of the
+// same kind and name, like the CRDs and ClusterRoles, is part of the manifests of any embedded version.
+func adoptLegacyObjects(ctx context.Context, c client.Client, embedded embeddedObjects) error {
+	selector := labels.SelectorFromSet(labels.Set{manifest.LabelManagedBy: manifest.LabelManagedByValue})
+	noPartOf, err := labels.NewRequirement(manifest.LabelPartOf, selection.DoesNotExist, nil)
 	if err != nil {
 		return err
 	}
+	selector = selector.Add(*noPartOf)
+
+	for _, gvk := range embedded.kinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+		}
+
+		for _, item := range list.Items {
+			if item.GetNamespace() != namespace && (item.GetNamespace() != "" || !embedded.names[objectID{gvk, item.GetName()}]) {
+				continue
+			}
+			patch := client.MergeFrom(item.DeepCopy())
+			itemLabels := item.GetLabels()
+			itemLabels[manifest.LabelPartOf] = labelPartOfValue
+			item.SetLabels(itemLabels)
+			if err := c.Patch(ctx, &item, patch); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to adopt object %s: %w", manifest.ObjectName(&item), err)
+			}
+		}
+	}
+	return nil
+}
+
+// AdvertisementConfig configures how MetalLB advertises the LoadBalancer IPs.
+type AdvertisementConfig struct {
+	// BGP enables the BGP mode. If nil, the L2 mode is used.
+	BGP *BGPConfig
+}
+
+// BGPConfig configures the BGP session between MetalLB and a router.
+type BGPConfig struct {
+	// PeerAddress is the address of the router.
+	PeerAddress net.IP
+	// PeerASN is the autonomous system number of the router.
+	PeerASN int64
+	// MyASN is the autonomous system number MetalLB uses.
+	MyASN int64
+}
 
-	objs, err := toUnstructured(r)
+// Uninstall removes the MetalLB configuration and all MetalLB components from the cluster.
+func Uninstall(ctx context.Context, c client.Client) error {
+	for _, gvk := range []schema.GroupVersionKind{ipAddressPoolGVK, l2AdvertisementGVK, bgpAdvertisementGVK, bgpPeerGVK} {
+		if err := deleteObject(ctx, c, gvk); err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+
+	embedded, err := loadEmbeddedObjects()
 	if err != nil {
 		return err
 	}
+	if err := adoptLegacyObjects(ctx, c, embedded); err != nil {
+		return err
+	}
 
-	return createObjects(ctx, c, objs)
+	return manifest.Prune(ctx, c, nil, embedded.kinds, pruneLabels, &manifest.Result{})
 }
 
-// ConfigureSubnet configures the MetalLB subnet for the cluster.
-func ConfigureSubnet(ctx context.Context, c client.Client, subnet net.IPNet) error {
+// ConfigureSubnet configures the MetalLB subnet for the cluster and how it is advertised.
+func ConfigureSubnet(ctx context.Context, c client.Client, subnet net.IPNet, adv AdvertisementConfig) error {
+	if adv.BGP != nil {
+		return errors.Join(
+			configureIPAddressPool(ctx, c, subnet),
+			configureBGPPeer(ctx, c, *adv.BGP),
+			configureBGPAdvertisement(ctx, c),
+			deleteObject(ctx, c, l2AdvertisementGVK),
+		)
+	}
+
 	return errors.Join(
 		configureIPAddressPool(ctx, c, subnet),
 		configureL2Advertisement(ctx, c),
+		deleteObject(ctx, c, bgpPeerGVK),
+		deleteObject(ctx, c, bgpAdvertisementGVK),
 	)
 }
 
 func configureIPAddressPool(ctx context.Context, c client.Client, subnet net.IPNet) error {
-	pool := &unstructured.Unstructured{}
-	pool.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "metallb.io",
-		Version: "v1beta1",
-		Kind:    "IPAddressPool",
-	})
-	pool.SetName("kind")
-	pool.SetNamespace(namespace)
+	pool := newObject(ipAddressPoolGVK)
 
 	_, err := controllerutil.CreateOrUpdate(ctx, c, pool, func() error {
 		pool.Object["spec"] = map[string]any{
-			"addresses": []string{
+			"addresses": []any{
 				subnet.String(),
 			},
 			"avoidBuggyIPs": true,
@@ -79,14 +227,7 @@ func configureIPAddressPool(ctx context.Context, c client.Client, subnet net.IPN
 }
 
 func configureL2Advertisement(ctx context.Context, c client.Client) error {
-	l2a := &unstructured.Unstructured{}
-	l2a.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "metallb.io",
-		Version: "v1beta1",
-		Kind:    "L2Advertisement",
-	})
-	l2a.SetName("kind")
-	l2a.SetNamespace(namespace)
+	l2a := newObject(l2AdvertisementGVK)
 
 	_, err := controllerutil.CreateOrUpdate(ctx, c, l2a, func() error {
 		// nothing to update
@@ -95,89 +236,105 @@ func configureL2Advertisement(ctx context.Context, c client.Client) error {
 	return err
 }
 
-func toUnstructured(r resmap.ResMap) ([]*unstructured.Unstructured, error) {
-	objs := []*unstructured.Unstructured{}
+func configureBGPPeer(ctx context.Context, c client.Client, cfg BGPConfig) error {
+	peer := newObject(bgpPeerGVK)
 
-	for _, res := range r.Resources() {
-		yamlBytes, err := res.AsYAML()
-		if err != nil {
-			return nil, err
+	_, err := controllerutil.CreateOrUpdate(ctx, c, peer, func() error {
+		peer.Object["spec"] = map[string]any{
+			"peerAddress": cfg.PeerAddress.String(),
+			"peerASN":     cfg.PeerASN,
+			"myASN":       cfg.MyASN,
 		}
+		return nil
+	})
+	return err
+}
 
-		u := &unstructured.Unstructured{}
-		if err := yaml.Unmarshal(yamlBytes, u); err != nil {
-			return nil, err
-		}
+func configureBGPAdvertisement(ctx context.Context, c client.Client) error {
+	bgpa := newObject(bgpAdvertisementGVK)
 
-		objs = append(objs, u)
-	}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, bgpa, func() error {
+		bgpa.Object["spec"] = map[string]any{
+			"ipAddressPools": []any{
+				objectNameKind,
+			},
+		}
+		return nil
+	})
+	return err
+}
 
-	return objs, nil
+func deleteObject(ctx context.Context, c client.Client, gvk schema.GroupVersionKind) error {
+	return client.IgnoreNotFound(c.Delete(ctx, newObject(gvk)))
 }
 
-func createObjects(ctx context.Context, c client.Client, objs []*unstructured.Unstructured) error {
-	for _, obj := range objs {
-		if err := c.Create(ctx, obj); client.IgnoreAlreadyExists(err) != nil {
-			return fmt.Errorf("failed to create object: %v", err)
-		}
-	}
+// newObject returns the MetalLB configuration object of the given kind that is managed by the provider.
+func newObject(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(objectNameKind)
+	obj.SetNamespace(namespace)
+	return obj
+}
 
-	return nil
+// objectID identifies an object of the manifests by kind and name.
+type objectID struct {
+	gvk  schema.GroupVersionKind
+	name string
 }
 
-func build() (resmap.ResMap, error) {
-	fs := filesys.MakeFsInMemory()
+// embeddedObjects describes the objects in the embedded manifests of all versions.
+type embeddedObjects struct {
+	// kinds are the kinds of all objects. Objects of these kinds are considered for pruning, so that objects of a
+	// previous version are removed as well.
+	kinds []schema.GroupVersionKind
+	// names contains the kind and name of all objects.
+	names map[objectID]bool
+}
 
-	err := errors.Join(
-		addBaseYAMLToFS(fs),
-		addKustomizationToFS(fs, nil),
-	)
-	if err != nil {
-		return nil, err
-	}
+// loadEmbeddedObjects returns the objects in the embedded manifests of all versions.
+// The manifests do not change at runtime, so they are only parsed once.
+var loadEmbeddedObjects = sync.OnceValues(parseEmbeddedObjects)
 
-	// Recover if Kustomize panics
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("kustomize panic: %v", r)
+func parseEmbeddedObjects() (embeddedObjects, error) {
+	embedded := embeddedObjects{names: map[objectID]bool{}}
+	for _, version := range Versions() {
+		data, err := manifests.Read(path.Join(version, resourceBaseYAML))
+		if err != nil {
+			return embeddedObjects{}, err
 		}
-	}()
 
-	k := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
-	return k.Run(fs, ".")
-}
-
-func addBaseYAMLToFS(fs filesys.FileSystem) error {
-	return fs.WriteFile(resourceBaseYAML, metallbYAML)
+		decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+		for {
+			obj := metav1.PartialObjectMetadata{}
+			if err := decoder.Decode(&obj); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return embeddedObjects{}, err
+			}
+			if obj.Kind == "" {
+				continue
+			}
+			gvk := schema.FromAPIVersionAndKind(obj.APIVersion, obj.Kind)
+			if !slices.Contains(embedded.kinds, gvk) {
+				embedded.kinds = append(embedded.kinds, gvk)
+			}
+			embedded.names[objectID{gvk, obj.Name}] = true
+		}
+	}
+	return embedded, nil
 }
 
-func addKustomizationToFS(fs filesys.FileSystem, images []types.Image) error {
-	k := getKustomization(images)
-	jsonBytes, err := json.Marshal(k)
+func build(opts Options) ([]*unstructured.Unstructured, error) {
+	version, err := ResolveVersion(opts.Version)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return fs.WriteFile(resourceKustomization, jsonBytes)
-}
-
-func getKustomization(images []types.Image) *types.Kustomization {
-	return &types.Kustomization{
-		TypeMeta: types.TypeMeta{
-			Kind:       types.KustomizationKind,
-			APIVersion: types.KustomizationVersion,
-		},
+	return manifests.Render(path.Join(version, resourceBaseYAML), manifest.RenderOptions{
+		PartOf:    labelPartOfValue,
 		Namespace: namespace,
-		Resources: []string{
-			resourceBaseYAML,
-		},
-		Images: images,
-		Labels: []types.Label{
-			{
-				Pairs: map[string]string{
-					"app.kubernetes.io/managed-by": "cluster-provider-kind",
-				},
-			},
-		},
-	}
+		Images:    opts.Images,
+	})
 }
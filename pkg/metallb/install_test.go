@@ -0,0 +1,276 @@
+package metallb
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest/manifesttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func Test_build(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		opts           Options
+		expectedImages []string
+		expectedErr    error
+	}{
+		{
+			desc: "should use default version and images",
+			opts: Options{},
+			expectedImages: []string{
+				"quay.io/metallb/controller:v0.14.9",
+				"quay.io/metallb/speaker:v0.14.9",
+			},
+		},
+		{
+			desc: "should override images",
+			opts: Options{
+				Version: "v0.14.9",
+				Images: []types.Image{
+					{Name: "quay.io/metallb/controller", NewName: "registry.local/metallb/controller"},
+					{Name: "quay.io/metallb/speaker", NewName: "registry.local/metallb/speaker", NewTag: "custom"},
+				},
+			},
+			expectedImages: []string{
+				"registry.local/metallb/controller:v0.14.9",
+				"registry.local/metallb/speaker:custom",
+			},
+		},
+		{
+			desc:        "should fail for unknown version",
+			opts:        Options{Version: "v0.0.1"},
+			expectedErr: ErrUnknownVersion,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			objs, err := build(tC.opts)
+			if tC.expectedErr != nil {
+				assert.ErrorIs(t, err, tC.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			images := []string{}
+			for _, obj := range objs {
+				containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+				for _, c := range containers {
+					images = append(images, c.(map[string]any)["image"].(string))
+				}
+			}
+			assert.ElementsMatch(t, tC.expectedImages, images)
+		})
+	}
+}
+
+func TestVersions(t *testing.T) {
+	assert.Contains(t, Versions(), DefaultVersion)
+}
+
+// Test_build_embedded renders the embedded manifests of every version, so that a broken or misplaced manifest fails
+// the build instead of the installation in a cluster.
+func Test_build_embedded(t *testing.T) {
+	for _, version := range Versions() {
+		t.Run(version, func(t *testing.T) {
+			objs, err := build(Options{Version: version})
+			require.NoError(t, err)
+
+			images := []string{}
+			for _, obj := range objs {
+				containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+				for _, c := range containers {
+					images = append(images, c.(map[string]any)["image"].(string))
+				}
+			}
+			assert.ElementsMatch(t, []string{"quay.io/metallb/controller:" + version, "quay.io/metallb/speaker:" + version}, images)
+		})
+	}
+
+	embedded, err := loadEmbeddedObjects()
+	require.NoError(t, err)
+	assert.Contains(t, embedded.kinds, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	assert.Contains(t, embedded.kinds, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"})
+}
+
+func TestInstall(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+
+	result, err := Install(ctx, c, Options{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Created)
+	assert.Empty(t, result.Updated)
+	assert.Empty(t, result.Pruned)
+
+	// a second install must not change anything
+	result, err = Install(ctx, c, Options{})
+	assert.NoError(t, err)
+	assert.False(t, result.HasChanges(), result.String())
+
+	// changing an image must update the deployment in place
+	result, err = Install(ctx, c, Options{
+		Images: []types.Image{{Name: "quay.io/metallb/controller", NewTag: "custom"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Deployment/metallb-system/controller"}, result.Updated)
+
+	// objects that are no longer part of the manifests must be pruned
+	orphan := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "orphan",
+			Namespace: namespace,
+			Labels: map[string]string{
+				manifest.LabelManagedBy: manifest.LabelManagedByValue,
+				manifest.LabelPartOf:    labelPartOfValue,
+			},
+		},
+	}
+	assert.NoError(t, c.Create(ctx, orphan))
+
+	result, err = Install(ctx, c, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ConfigMap/metallb-system/orphan"}, result.Pruned)
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(orphan), orphan)))
+}
+
+func TestInstall_pruneLegacyObjects(t *testing.T) {
+	ctx := context.Background()
+	// earlier versions of the provider only set the managed-by label
+	legacyLabels := map[string]string{manifest.LabelManagedBy: manifest.LabelManagedByValue}
+	stale := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: namespace, Labels: legacyLabels}}
+	// cluster-scoped objects are adopted if they are part of the manifests and updated in place
+	legacyClusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "metallb-system:controller", Labels: legacyLabels}}
+	// objects of the provider outside of MetalLB, e.g. of manifest bundles, must be kept,
+	// even if their name contains "metallb"
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", Labels: legacyLabels}}
+	otherClusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "metallb-dashboard-viewer", Labels: legacyLabels}}
+	c := fake.NewClientBuilder().WithObjects(stale, legacyClusterRole, other, otherClusterRole).Build()
+
+	result, err := Install(ctx, c, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ConfigMap/metallb-system/stale"}, result.Pruned)
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(stale), stale)))
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(legacyClusterRole), legacyClusterRole))
+	assert.Equal(t, labelPartOfValue, legacyClusterRole.Labels[manifest.LabelPartOf])
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(other), other))
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(otherClusterRole), otherClusterRole))
+	assert.NotContains(t, other.Labels, manifest.LabelPartOf)
+	assert.NotContains(t, otherClusterRole.Labels, manifest.LabelPartOf)
+}
+
+func withTestManifests(t *testing.T, files fstest.MapFS) {
+	t.Helper()
+	manifesttest.WithFS(t, manifests, files)
+	original := loadEmbeddedObjects
+	loadEmbeddedObjects = sync.OnceValues(parseEmbeddedObjects)
+	t.Cleanup(func() { loadEmbeddedObjects = original })
+}
+
+func controllerDeployment(version string) string {
+	return `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller
+spec:
+  template:
+    spec:
+      containers:
+      - name: controller
+        image: quay.io/metallb/controller:` + version + `
+`
+}
+
+func TestInstall_upgrade(t *testing.T) {
+	withTestManifests(t, fstest.MapFS{
+		"manifests/v0.14.9/metallb-native.yaml": {Data: []byte(controllerDeployment("v0.14.9") + `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: excludel2
+`)},
+		"manifests/v0.15.2/metallb-native.yaml": {Data: []byte(controllerDeployment("v0.15.2") + `---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: speaker
+`)},
+	})
+	assert.Equal(t, []string{"v0.14.9", "v0.15.2"}, Versions())
+
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+
+	result, err := Install(ctx, c, Options{Version: "v0.14.9"})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Deployment/metallb-system/controller", "ConfigMap/metallb-system/excludel2"}, result.Created)
+
+	// the upgrade updates the objects of both versions and prunes the objects of the previous version
+	result, err = Install(ctx, c, Options{Version: "v0.15.2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ServiceAccount/metallb-system/speaker"}, result.Created)
+	assert.Equal(t, []string{"Deployment/metallb-system/controller"}, result.Updated)
+	assert.Equal(t, []string{"ConfigMap/metallb-system/excludel2"}, result.Pruned)
+
+	deployment := &unstructured.Unstructured{}
+	deployment.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	assert.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "controller"}, deployment))
+	containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+	assert.Equal(t, "quay.io/metallb/controller:v0.15.2", containers[0].(map[string]any)["image"])
+
+	// downgrades are possible as well
+	result, err = Install(ctx, c, Options{Version: "v0.14.9"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ConfigMap/metallb-system/excludel2"}, result.Created)
+	assert.Equal(t, []string{"ServiceAccount/metallb-system/speaker"}, result.Pruned)
+}
+
+func TestConfigureSubnet(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	subnet := net.IPNet{IP: net.IPv4(172, 19, 200, 0), Mask: net.CIDRMask(24, 32)}
+
+	exists := func(gvk schema.GroupVersionKind) bool {
+		err := c.Get(ctx, client.ObjectKeyFromObject(newObject(gvk)), newObject(gvk))
+		return err == nil
+	}
+
+	assert.NoError(t, ConfigureSubnet(ctx, c, subnet, AdvertisementConfig{}))
+	assert.True(t, exists(ipAddressPoolGVK))
+	assert.True(t, exists(l2AdvertisementGVK))
+	assert.False(t, exists(bgpPeerGVK))
+
+	// switching to BGP mode replaces the L2 advertisement
+	assert.NoError(t, ConfigureSubnet(ctx, c, subnet, AdvertisementConfig{
+		BGP: &BGPConfig{PeerAddress: net.IPv4(172, 19, 0, 10), PeerASN: 64512, MyASN: 64513},
+	}))
+	assert.False(t, exists(l2AdvertisementGVK))
+	assert.True(t, exists(bgpPeerGVK))
+	assert.True(t, exists(bgpAdvertisementGVK))
+
+	peer := newObject(bgpPeerGVK)
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(peer), peer))
+	peerAddress, _, _ := unstructured.NestedString(peer.Object, "spec", "peerAddress")
+	assert.Equal(t, "172.19.0.10", peerAddress)
+
+	// switching back to L2 mode removes the BGP configuration
+	assert.NoError(t, ConfigureSubnet(ctx, c, subnet, AdvertisementConfig{}))
+	assert.True(t, exists(l2AdvertisementGVK))
+	assert.False(t, exists(bgpPeerGVK))
+	assert.False(t, exists(bgpAdvertisementGVK))
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+)
+
+const (
+	collectTimeout = 10 * time.Second
+)
+
+var (
+	clustersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "clusters"),
+		"Number of kind clusters by phase.",
+		[]string{"phase"}, nil,
+	)
+)
+
+// NewClusterCollector returns a collector that reports the number of Clusters by phase when it is scraped.
+// Only the Clusters for which the filter returns true are counted.
+func NewClusterCollector(c client.Reader, filter func(*clustersv1alpha1.Cluster) bool) prometheus.Collector {
+	return &clusterCollector{client: c, filter: filter}
+}
+
+type clusterCollector struct {
+	client client.Reader
+	filter func(*clustersv1alpha1.Cluster) bool
+}
+
+// Describe implements prometheus.Collector.
+func (c *clusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- clustersDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *clusterCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	clusters := &clustersv1alpha1.ClusterList{}
+	if err := c.client.List(ctx, clusters); err != nil {
+		logf.Log.WithName("metrics").Error(err, "Failed to list clusters")
+		ch <- prometheus.NewInvalidMetric(clustersDesc, err)
+		return
+	}
+
+	phases := map[string]int{}
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if !c.filter(cluster) {
+			continue
+		}
+		phase := cluster.Status.Phase
+		if phase == "" {
+			phase = clustersv1alpha1.CLUSTER_PHASE_UNKNOWN
+		}
+		phases[phase]++
+	}
+
+	for phase, count := range phases {
+		ch <- prometheus.MustNewConstMetric(clustersDesc, prometheus.GaugeValue, float64(count), phase)
+	}
+}
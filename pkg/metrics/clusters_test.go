@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	commonapi "github.com/openmcp-project/openmcp-operator/api/common"
+)
+
+func TestClusterCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clustersv1alpha1.AddToScheme(scheme))
+
+	cluster := func(name, profile string, phase string) *clustersv1alpha1.Cluster {
+		return &clustersv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       clustersv1alpha1.ClusterSpec{Profile: profile},
+			Status: clustersv1alpha1.ClusterStatus{
+				Status: commonapi.Status{Phase: phase},
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		cluster("one", "kind", clustersv1alpha1.CLUSTER_PHASE_READY),
+		cluster("two", "kind", clustersv1alpha1.CLUSTER_PHASE_READY),
+		cluster("three", "kind", ""),
+		cluster("four", "gardener", clustersv1alpha1.CLUSTER_PHASE_READY),
+	).Build()
+
+	collector := NewClusterCollector(c, func(cluster *clustersv1alpha1.Cluster) bool {
+		return cluster.Spec.Profile == "kind"
+	})
+
+	expected := `
+# HELP cluster_provider_kind_clusters Number of kind clusters by phase.
+# TYPE cluster_provider_kind_clusters gauge
+cluster_provider_kind_clusters{phase="Ready"} 2
+cluster_provider_kind_clusters{phase="Unknown"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
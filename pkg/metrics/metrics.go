@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "cluster_provider_kind"
+
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+var (
+	// ClusterCreateDuration observes the duration of kind cluster creations.
+	ClusterCreateDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "cluster_create_duration_seconds",
+		Help:      "Duration of kind cluster creations.",
+		Buckets:   []float64{15, 30, 60, 90, 120, 180, 300, 600},
+	}, []string{"result"})
+
+	// ClusterDeleteDuration observes the duration of kind cluster deletions.
+	ClusterDeleteDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "cluster_delete_duration_seconds",
+		Help:      "Duration of kind cluster deletions.",
+		Buckets:   []float64{1, 2, 5, 10, 20, 30, 60, 120},
+	}, []string{"result"})
+
+	// LoadBalancerSubnetsUsed is the number of LoadBalancer subnets that are assigned to clusters in a Docker network
+	// of a Docker host.
+	LoadBalancerSubnetsUsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "lb_subnets_used",
+		Help:      "Number of LoadBalancer subnets assigned to clusters in a Docker network of a Docker host.",
+	}, []string{"host", "network"})
+
+	// LoadBalancerSubnetsTotal is the number of LoadBalancer subnets that can be assigned in a Docker network of a
+	// Docker host.
+	LoadBalancerSubnetsTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "lb_subnets_total",
+		Help:      "Number of LoadBalancer subnets that can be assigned to clusters in a Docker network of a Docker host.",
+	}, []string{"host", "network"})
+
+	// MetalLBInstallFailures counts the failed installations of MetalLB.
+	MetalLBInstallFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "metallb_install_failures_total",
+		Help:      "Number of failed MetalLB installations.",
+	})
+
+	// AccessTokenExpiration is the expiration time of the token issued for an AccessRequest.
+	AccessTokenExpiration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "access_request_token_expiration_timestamp_seconds",
+		Help:      "Expiration time of the token issued for an AccessRequest, in seconds since the epoch.",
+	}, []string{"namespace", "name"})
+
+	// DockerCommandDuration observes the duration of docker commands.
+	DockerCommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "docker_command_duration_seconds",
+		Help:      "Duration of docker commands.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"command"})
+
+	// DockerCommandErrors counts the failed docker commands.
+	DockerCommandErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "docker_command_errors_total",
+		Help:      "Number of failed docker commands.",
+	}, []string{"command"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ClusterCreateDuration,
+		ClusterDeleteDuration,
+		LoadBalancerSubnetsUsed,
+		LoadBalancerSubnetsTotal,
+		MetalLBInstallFailures,
+		AccessTokenExpiration,
+		DockerCommandDuration,
+		DockerCommandErrors,
+	)
+}
+
+// ObserveDuration records the time since start in the given histogram, labeled with the result of the operation.
+func ObserveDuration(histogram *prometheus.HistogramVec, start time.Time, err error) {
+	histogram.WithLabelValues(result(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDockerCommand records the duration and the failure of a docker command.
+func ObserveDockerCommand(command string, duration time.Duration, err error) {
+	DockerCommandDuration.WithLabelValues(command).Observe(duration.Seconds())
+	if err != nil {
+		DockerCommandErrors.WithLabelValues(command).Inc()
+	}
+}
+
+// ResetLoadBalancerSubnets removes the LoadBalancer subnet metrics of all Docker networks of the given Docker host.
+func ResetLoadBalancerSubnets(host string) {
+	LoadBalancerSubnetsUsed.DeletePartialMatch(prometheus.Labels{"host": host})
+	LoadBalancerSubnetsTotal.DeletePartialMatch(prometheus.Labels{"host": host})
+}
+
+// Register registers the given collector on the controller-runtime metrics registry.
+// It returns a prometheus.AlreadyRegisteredError if an equal collector has already been registered.
+func Register(c prometheus.Collector) error {
+	return metrics.Registry.Register(c)
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultError
+	}
+	return resultSuccess
+}
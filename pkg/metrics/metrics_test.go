@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveDockerCommand(t *testing.T) {
+	ObserveDockerCommand("test run", time.Second, nil)
+	ObserveDockerCommand("test run", time.Second, errors.New("exit status 1"))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(DockerCommandErrors.WithLabelValues("test run")))
+	assert.Equal(t, 1, testutil.CollectAndCount(DockerCommandDuration, namespace+"_docker_command_duration_seconds"))
+}
+
+func TestObserveDuration(t *testing.T) {
+	ObserveDuration(ClusterCreateDuration, time.Now().Add(-time.Minute), nil)
+	ObserveDuration(ClusterCreateDuration, time.Now().Add(-time.Minute), errors.New("failed"))
+
+	assert.Equal(t, 2, testutil.CollectAndCount(ClusterCreateDuration))
+}
+
+func TestResetLoadBalancerSubnets(t *testing.T) {
+	LoadBalancerSubnetsUsed.WithLabelValues("test", "kind").Set(2)
+	LoadBalancerSubnetsUsed.WithLabelValues("other", "kind").Set(1)
+
+	ResetLoadBalancerSubnets("test")
+	assert.Equal(t, 1, testutil.CollectAndCount(LoadBalancerSubnetsUsed))
+}
+
+func TestRegister(t *testing.T) {
+	var are prometheus.AlreadyRegisteredError
+	assert.ErrorAs(t, Register(MetalLBInstallFailures), &are)
+}
@@ -0,0 +1,90 @@
+package preload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+const (
+	// ConditionImagesPreloaded reports whether all images of the ProviderConfig have been loaded into the nodes.
+	ConditionImagesPreloaded = "ImagesPreloaded"
+)
+
+// Reconcile loads the images of the ProviderConfig into all nodes of the kind cluster.
+// Images that have been loaded according to the given status of the previous reconciliation are skipped.
+// A failing image does not prevent the other images from being loaded. The state of each image is reported in the
+// provider status, the overall state as condition on the Cluster.
+func Reconcile(ctx context.Context, provider kind.Provider, ac addon.Context, previous []v1alpha1.PreloadedImageStatus) (bool, error) {
+	images := ac.Config.Spec.PreloadImages
+	if len(images) == 0 {
+		ac.ProviderStatus.PreloadedImages = nil
+		meta.RemoveStatusCondition(&ac.Cluster.Status.Conditions, ConditionImagesPreloaded)
+		return true, nil
+	}
+
+	log := logf.FromContext(ctx)
+
+	statuses := make([]v1alpha1.PreloadedImageStatus, 0, len(images))
+	var errs []error
+	var failed []string
+	for _, image := range images {
+		name := imageName(image)
+		if slices.ContainsFunc(previous, func(s v1alpha1.PreloadedImageStatus) bool { return s.Name == name && s.Loaded }) {
+			statuses = append(statuses, v1alpha1.PreloadedImageStatus{Name: name, Loaded: true})
+			continue
+		}
+
+		log.Info("Loading image into nodes", "image", name)
+		if err := load(ctx, provider, ac.KindName, image); err != nil {
+			statuses = append(statuses, v1alpha1.PreloadedImageStatus{Name: name, Message: err.Error()})
+			errs = append(errs, fmt.Errorf("failed to preload image %s: %w", name, err))
+			failed = append(failed, name)
+			continue
+		}
+
+		statuses = append(statuses, v1alpha1.PreloadedImageStatus{Name: name, Loaded: true})
+		ac.Recorder.Eventf(ac.Cluster, nil, corev1.EventTypeNormal, "ImagePreloaded", "PreloadImage", "Loaded image %s into all nodes", name)
+	}
+	ac.ProviderStatus.PreloadedImages = statuses
+
+	condition := metav1.Condition{
+		Type:   ConditionImagesPreloaded,
+		Status: metav1.ConditionTrue,
+		Reason: "ImagesLoaded",
+	}
+	if len(failed) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PreloadFailed"
+		condition.Message = fmt.Sprintf("Failed to load images: %s", strings.Join(failed, ", "))
+	}
+	meta.SetStatusCondition(&ac.Cluster.Status.Conditions, condition)
+
+	return condition.Status == metav1.ConditionTrue, errors.Join(errs...)
+}
+
+func load(ctx context.Context, provider kind.Provider, clusterName string, image v1alpha1.PreloadImage) error {
+	if image.Archive != "" {
+		return provider.LoadImageArchive(ctx, clusterName, image.Archive)
+	}
+	return provider.LoadImage(ctx, clusterName, image.Image)
+}
+
+// imageName returns the name of the image in the status.
+func imageName(image v1alpha1.PreloadImage) string {
+	if image.Archive != "" {
+		return image.Archive
+	}
+	return image.Image
+}
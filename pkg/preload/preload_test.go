@@ -0,0 +1,136 @@
+package preload
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+type fakeProvider struct {
+	kind.Provider
+	loaded []string
+	failOn string
+}
+
+func (p *fakeProvider) LoadImage(_ context.Context, _ string, image string) error {
+	if image == p.failOn {
+		return errors.New("pull access denied")
+	}
+	p.loaded = append(p.loaded, image)
+	return nil
+}
+
+func (p *fakeProvider) LoadImageArchive(_ context.Context, _ string, file string) error {
+	p.loaded = append(p.loaded, file)
+	return nil
+}
+
+func TestReconcile(t *testing.T) {
+	images := []v1alpha1.PreloadImage{
+		{Image: "nginx:1.27"},
+		{Archive: "/images/app.tar"},
+		{Image: "private/app:1.0"},
+	}
+
+	testCases := []struct {
+		desc             string
+		images           []v1alpha1.PreloadImage
+		previous         []v1alpha1.PreloadedImageStatus
+		failOn           string
+		expectedReady    bool
+		expectedLoaded   []string
+		expectedStatuses []v1alpha1.PreloadedImageStatus
+	}{
+		{
+			desc:           "should load all images",
+			images:         images,
+			expectedReady:  true,
+			expectedLoaded: []string{"nginx:1.27", "/images/app.tar", "private/app:1.0"},
+			expectedStatuses: []v1alpha1.PreloadedImageStatus{
+				{Name: "nginx:1.27", Loaded: true},
+				{Name: "/images/app.tar", Loaded: true},
+				{Name: "private/app:1.0", Loaded: true},
+			},
+		},
+		{
+			desc:   "should skip loaded images and retry failed ones",
+			images: images,
+			previous: []v1alpha1.PreloadedImageStatus{
+				{Name: "nginx:1.27", Loaded: true},
+				{Name: "/images/app.tar", Message: "no such file"},
+			},
+			expectedReady:  true,
+			expectedLoaded: []string{"/images/app.tar", "private/app:1.0"},
+			expectedStatuses: []v1alpha1.PreloadedImageStatus{
+				{Name: "nginx:1.27", Loaded: true},
+				{Name: "/images/app.tar", Loaded: true},
+				{Name: "private/app:1.0", Loaded: true},
+			},
+		},
+		{
+			desc:           "should report failed image and continue with the others",
+			images:         images,
+			failOn:         "private/app:1.0",
+			expectedReady:  false,
+			expectedLoaded: []string{"nginx:1.27", "/images/app.tar"},
+			expectedStatuses: []v1alpha1.PreloadedImageStatus{
+				{Name: "nginx:1.27", Loaded: true},
+				{Name: "/images/app.tar", Loaded: true},
+				{Name: "private/app:1.0", Message: "pull access denied"},
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			provider := &fakeProvider{failOn: tC.failOn}
+			cluster := &clustersv1alpha1.Cluster{}
+			ac := addon.Context{
+				Cluster:        cluster,
+				KindName:       "test",
+				Config:         &v1alpha1.ProviderConfig{Spec: v1alpha1.ProviderConfigSpec{PreloadImages: tC.images}},
+				ProviderStatus: &v1alpha1.ClusterStatus{},
+				Recorder:       events.NewFakeRecorder(10),
+			}
+
+			ready, err := Reconcile(context.Background(), provider, ac, tC.previous)
+			assert.Equal(t, tC.expectedReady, ready)
+			if tC.expectedReady {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tC.failOn)
+			}
+			assert.Equal(t, tC.expectedLoaded, provider.loaded)
+			assert.Equal(t, tC.expectedStatuses, ac.ProviderStatus.PreloadedImages)
+
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionImagesPreloaded)
+			assert.NotNil(t, condition)
+			assert.Equal(t, tC.expectedReady, condition.Status == metav1.ConditionTrue)
+		})
+	}
+}
+
+func TestReconcile_noImages(t *testing.T) {
+	cluster := &clustersv1alpha1.Cluster{}
+	ac := addon.Context{
+		Cluster:        cluster,
+		Config:         &v1alpha1.ProviderConfig{},
+		ProviderStatus: &v1alpha1.ClusterStatus{PreloadedImages: []v1alpha1.PreloadedImageStatus{{Name: "nginx:1.27", Loaded: true}}},
+	}
+
+	ready, err := Reconcile(context.Background(), &fakeProvider{}, ac, nil)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+	assert.Nil(t, ac.ProviderStatus.PreloadedImages)
+	assert.Nil(t, meta.FindStatusCondition(cluster.Status.Conditions, ConditionImagesPreloaded))
+}
@@ -0,0 +1,116 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/manifest"
+)
+
+const (
+	// AddonName is the name of the local registry addon.
+	AddonName = "LocalRegistry"
+
+	// configMapName and configMapNamespace identify the ConfigMap that documents the local registry, see
+	// https://github.com/kubernetes/enhancements/tree/master/keps/sig-cluster-lifecycle/generic/1755-communicating-a-local-registry
+	configMapName      = "local-registry-hosting"
+	configMapNamespace = "kube-public"
+	configMapKey       = "localRegistryHosting.v1"
+
+	labelPartOfValue = "local-registry"
+)
+
+// NewAddon returns the addon that runs a local registry container in the kind network, which is shared by all clusters.
+// The containerd of every node is configured to pull the images pushed to the registry from it.
+func NewAddon() addon.Addon {
+	return &registryAddon{}
+}
+
+var _ addon.Addon = &registryAddon{}
+var _ addon.ClusterConfigurer = &registryAddon{}
+
+type registryAddon struct{}
+
+// Name implements addon.Addon.
+func (a *registryAddon) Name() string {
+	return AddonName
+}
+
+// ConfigureCluster implements addon.ClusterConfigurer.
+func (a *registryAddon) ConfigureCluster(cfg *kind.ClusterConfig) {
+	cfg.ContainerdConfigPatches = append(cfg.ContainerdConfigPatches, kind.RegistryContainerdConfigPatch)
+}
+
+// Install implements addon.Addon.
+func (a *registryAddon) Install(ctx context.Context, ac addon.Context) error {
+	cfg := registryConfig(ac.Config)
+	if err := kind.EnsureRegistry(ctx, cfg); err != nil {
+		return err
+	}
+	if err := kind.ConnectRegistry(ctx, kind.NetworkFromCluster(ac.Cluster)); err != nil {
+		return err
+	}
+
+	cm := newConfigMap()
+	_, err := controllerutil.CreateOrUpdate(ctx, ac.Client, cm, func() error {
+		cm.Labels = map[string]string{
+			manifest.LabelManagedBy: manifest.LabelManagedByValue,
+			manifest.LabelPartOf:    labelPartOfValue,
+		}
+		cm.Data = map[string]string{
+			configMapKey: localRegistryHosting(cfg),
+		}
+		return nil
+	})
+	return err
+}
+
+// Ready implements addon.Addon.
+func (a *registryAddon) Ready(_ context.Context, _ addon.Context) (bool, error) {
+	return true, nil
+}
+
+// Configure implements addon.Addon.
+func (a *registryAddon) Configure(ctx context.Context, ac addon.Context) error {
+	cfg := registryConfig(ac.Config)
+	return kind.ConfigureRegistryMirror(ctx, ac.KindName, cfg.Host(), cfg.Endpoint())
+}
+
+// Uninstall implements addon.Addon.
+// The registry container is kept, since it is shared by all clusters.
+func (a *registryAddon) Uninstall(ctx context.Context, ac addon.Context) error {
+	return client.IgnoreNotFound(ac.Client.Delete(ctx, newConfigMap()))
+}
+
+func newConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName,
+			Namespace: configMapNamespace,
+		},
+	}
+}
+
+func localRegistryHosting(cfg kind.RegistryConfig) string {
+	return fmt.Sprintf(`host: %q
+hostFromClusterNetwork: %q
+help: "https://kind.sigs.k8s.io/docs/user/local-registry/"
+`, cfg.Host(), cfg.Endpoint())
+}
+
+func registryConfig(pc *v1alpha1.ProviderConfig) kind.RegistryConfig {
+	cfg := ptr.Deref(pc.Spec.LocalRegistry, v1alpha1.LocalRegistryConfig{})
+	return kind.RegistryConfig{
+		Image:    cfg.Image,
+		HostPort: cfg.HostPort,
+	}
+}
@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmcp-project/cluster-provider-kind/api/v1alpha1"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/addon"
+	"github.com/openmcp-project/cluster-provider-kind/pkg/kind"
+)
+
+func Test_registryConfig(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           *v1alpha1.LocalRegistryConfig
+		expectedHost     string
+		expectedEndpoint string
+	}{
+		{
+			desc:             "should use defaults",
+			config:           nil,
+			expectedHost:     "localhost:5001",
+			expectedEndpoint: "kind-registry:5000",
+		},
+		{
+			desc:             "should use configured host port",
+			config:           &v1alpha1.LocalRegistryConfig{HostPort: 5555},
+			expectedHost:     "localhost:5555",
+			expectedEndpoint: "kind-registry:5000",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			pc := &v1alpha1.ProviderConfig{Spec: v1alpha1.ProviderConfigSpec{LocalRegistry: tC.config}}
+			cfg := registryConfig(pc)
+			assert.Equal(t, tC.expectedHost, cfg.Host())
+			assert.Equal(t, tC.expectedEndpoint, cfg.Endpoint())
+		})
+	}
+}
+
+func Test_localRegistryHosting(t *testing.T) {
+	cfg := kind.RegistryConfig{HostPort: 5001}
+	expected := `host: "localhost:5001"
+hostFromClusterNetwork: "kind-registry:5000"
+help: "https://kind.sigs.k8s.io/docs/user/local-registry/"
+`
+	assert.Equal(t, expected, localRegistryHosting(cfg))
+}
+
+func TestConfigureCluster(t *testing.T) {
+	cfg := addon.ClusterConfig([]addon.Addon{NewAddon()})
+	assert.Equal(t, []string{kind.RegistryContainerdConfigPatch}, cfg.ContainerdConfigPatches)
+}
+
+func TestUninstall(t *testing.T) {
+	ctx := context.Background()
+	cm := newConfigMap()
+	c := fake.NewClientBuilder().WithObjects(cm).Build()
+	ac := addon.Context{
+		Client: c,
+		Config: &v1alpha1.ProviderConfig{},
+	}
+
+	assert.NoError(t, NewAddon().Uninstall(ctx, ac))
+	assert.Error(t, c.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}))
+
+	// uninstalling twice must not fail
+	assert.NoError(t, NewAddon().Uninstall(ctx, ac))
+}